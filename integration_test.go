@@ -72,7 +72,7 @@ func TestIntegration(t *testing.T) {
 	}
 
 	log := testLogger
-	server := daemon.New(socket, logstashPath, nil, log, 10*time.Second, 3*time.Second, 30*time.Second, noCleanup, 50*time.Millisecond)
+	server := daemon.New(socket, logstashPath, nil, log, 10*time.Second, 3*time.Second, 30*time.Second, noCleanup, 50*time.Millisecond, 2, nil)
 
 	version, err := standalonelogstash.DetectVersion(logstashPath, os.Environ())
 	is.NoErr(err)
@@ -118,6 +118,7 @@ func TestIntegration(t *testing.T) {
 
 		withoutPipeline bool
 		addMissingID    bool
+		batch           bool
 
 		pluginMock string
 	}{
@@ -152,6 +153,10 @@ func TestIntegration(t *testing.T) {
 		{
 			name: "codec_test",
 		},
+		{
+			name:  "codec_test",
+			batch: true,
+		},
 		{
 			name:           "codec_version_7_test",
 			maximumVersion: semver.MustParse("8.0.0"),
@@ -228,18 +233,20 @@ func TestIntegration(t *testing.T) {
 				logstashConfig = "testdata/" + tc.name
 			}
 
-			client, err := run.New(
-				filepath.Join(tempdir, "integration_test.socket"),
-				log,
-				pipeline,
-				pipelineBaseDir,
-				logstashConfig,
-				"testdata/testcases/"+tc.name,
-				tc.pluginMock,
-				"@metadata",
-				tc.debug,
-				tc.addMissingID,
-			)
+			client, err := run.New(run.Options{
+				Address:        filepath.Join(tempdir, "integration_test.socket"),
+				Pipeline:       pipeline,
+				PipelineBase:   pipelineBaseDir,
+				LogstashConfig: logstashConfig,
+				TestcasePath:   "testdata/testcases/" + tc.name,
+				PluginMock:     tc.pluginMock,
+				MetadataKey:    "@metadata",
+				Debug:          tc.debug,
+				AddMissingID:   tc.addMissingID,
+				OutputFormat:   "text",
+				Parallel:       1,
+				Batch:          tc.batch,
+			}, log)
 			is.NoErr(err)
 
 			for i := 0; i <= tc.repeat; i++ {
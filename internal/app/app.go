@@ -82,6 +82,9 @@ func makeRootCmd(version string) *cobra.Command {
 	rootCmd.AddCommand(makeStandaloneCmd())
 	rootCmd.AddCommand(makeDaemonCmd())
 	rootCmd.AddCommand(makeSetupCmd())
+	rootCmd.AddCommand(makeGenerateCmd())
+	rootCmd.AddCommand(makeLintCmd())
+	rootCmd.AddCommand(makeFixIDsCmd())
 
 	return rootCmd
 }
@@ -3,6 +3,8 @@ package app
 import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/transport"
 )
 
 func makeDaemonCmd() *cobra.Command {
@@ -13,10 +15,37 @@ func makeDaemonCmd() *cobra.Command {
 
 	cmd.PersistentFlags().StringP("socket", "s", "", "location of the control socket")
 	_ = viper.BindPFlag("socket", cmd.PersistentFlags().Lookup("socket"))
+	cmd.PersistentFlags().String("daemon-address", "", "address of the daemon to connect to, either tcp://host:port or the path of the control socket; overrides --socket")
+	_ = viper.BindPFlag("daemon-address", cmd.PersistentFlags().Lookup("daemon-address"))
+	cmd.PersistentFlags().String("tls-cert", "", "PEM encoded certificate presented by the daemon or the client for TCP connections")
+	_ = viper.BindPFlag("tls-cert", cmd.PersistentFlags().Lookup("tls-cert"))
+	cmd.PersistentFlags().String("tls-key", "", "PEM encoded private key belonging to --tls-cert")
+	_ = viper.BindPFlag("tls-key", cmd.PersistentFlags().Lookup("tls-key"))
+	cmd.PersistentFlags().String("tls-ca", "", "PEM encoded CA certificate to verify the other side; if given to daemon start, clients are required to present a certificate signed by this CA")
+	_ = viper.BindPFlag("tls-ca", cmd.PersistentFlags().Lookup("tls-ca"))
 
 	cmd.AddCommand(makeDaemonStartCmd())
 	cmd.AddCommand(makeDaemonShutdownCmd())
+	cmd.AddCommand(makeDaemonStatusCmd())
 	cmd.AddCommand(makeDaemonRunCmd())
 
 	return cmd
 }
+
+// daemonAddress returns the address, the clients connect to the daemon,
+// which is --daemon-address if given, otherwise --socket.
+func daemonAddress(get func(key string) string) string {
+	if address := get("daemon-address"); address != "" {
+		return address
+	}
+	return get("socket")
+}
+
+// tlsFiles returns the files configured for TLS.
+func tlsFiles(get func(key string) string) transport.TLSFiles {
+	return transport.TLSFiles{
+		Cert: get("tls-cert"),
+		Key:  get("tls-key"),
+		CA:   get("tls-ca"),
+	}
+}
@@ -4,9 +4,9 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"io"
-	"net"
 	"os"
 	"os/signal"
 	"sync"
@@ -16,6 +16,7 @@ import (
 	"github.com/Masterminds/semver/v3"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"gopkg.in/yaml.v2"
 
 	pb "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/api/grpc"
@@ -25,6 +26,7 @@ import (
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pipeline"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pool"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/session"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/transport"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
 	standalonelogstash "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logstash"
 )
@@ -42,7 +44,10 @@ type Daemon struct {
 	// shutdown to the shutdownSignalHandler.
 	shutdownSignalFunc context.CancelFunc
 
-	socket       string
+	// address is the path of the unix domain socket or tcp://host:port,
+	// the daemon listens on.
+	address      string
+	tlsConfig    *tls.Config
 	logstashPath string
 	keptEnvVars  []string
 
@@ -55,6 +60,13 @@ type Daemon struct {
 
 	noCleanup bool
 
+	poolSize int
+
+	// Information for the Status RPC, set by Run.
+	started         time.Time
+	logstashVersion string
+	pool            *pool.Pool
+
 	sessionController *session.Controller
 
 	server *grpc.Server
@@ -64,11 +76,15 @@ type Daemon struct {
 	pb.UnimplementedControlServer
 }
 
-// New creates a new logstash filter verifier daemon.
-func New(socket string, logstashPath string, keptEnvVars []string, log logging.Logger, inflightShutdownTimeout time.Duration, shutdownTimeout time.Duration, waitForStateTimeout time.Duration, noCleanup bool, waitForLateArrivalsTimeout time.Duration) Daemon {
+// New creates a new logstash filter verifier daemon. The daemon listens
+// on address, which is either the path of a unix domain socket or
+// tcp://host:port. If tlsConfig is not nil, TCP connections are secured with
+// TLS.
+func New(address string, logstashPath string, keptEnvVars []string, log logging.Logger, inflightShutdownTimeout time.Duration, shutdownTimeout time.Duration, waitForStateTimeout time.Duration, noCleanup bool, waitForLateArrivalsTimeout time.Duration, poolSize int, tlsConfig *tls.Config) Daemon {
 	ctxShutdownSignal, shutdownSignalFunc := context.WithCancel(context.Background())
 	return Daemon{
-		socket:                     socket,
+		address:                    address,
+		tlsConfig:                  tlsConfig,
 		logstashPath:               logstashPath,
 		keptEnvVars:                keptEnvVars,
 		inflightShutdownTimeout:    inflightShutdownTimeout,
@@ -79,6 +95,7 @@ func New(socket string, logstashPath string, keptEnvVars []string, log logging.L
 		waitForStateTimeout:        waitForStateTimeout,
 		noCleanup:                  noCleanup,
 		waitForLateArrivalsTimeout: waitForLateArrivalsTimeout,
+		poolSize:                   poolSize,
 	}
 }
 
@@ -91,6 +108,8 @@ func (d *Daemon) Run(ctx context.Context) error {
 	ctx, shutdown := context.WithCancel(ctxKill)
 	defer shutdown()
 
+	d.started = time.Now()
+
 	tempdir, err := os.MkdirTemp("", "lfv-")
 	if err != nil {
 		return err
@@ -104,6 +123,7 @@ func (d *Daemon) Run(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	d.logstashVersion = logstashVersion.String()
 	var isOrderedPipelineSupported bool
 	if logstashVersion.Compare(semver.MustParse("v7.7.0")) >= 0 {
 		isOrderedPipelineSupported = true
@@ -127,23 +147,37 @@ func (d *Daemon) Run(ctx context.Context) error {
 		return logstashController, nil
 	}
 
-	pool, err := pool.New(ctx, logstashControllerFactory, 2, d.log)
+	d.pool, err = pool.New(ctx, logstashControllerFactory, d.poolSize, d.log)
 	if err != nil {
 		return err
 	}
 
 	// Create Session Handler
-	d.sessionController = session.NewController(d.tempdir, pool, d.noCleanup, isOrderedPipelineSupported, d.log)
+	d.sessionController = session.NewController(d.tempdir, d.pool, d.poolSize, d.noCleanup, isOrderedPipelineSupported, d.log)
 
 	// Create and start GRPC Server
-	lis, err := net.Listen("unix", d.socket)
+	network, _, err := transport.Parse(d.address)
 	if err != nil {
 		return err
 	}
-	d.server = grpc.NewServer()
+	opts := []grpc.ServerOption{grpc.MaxRecvMsgSize(pb.MaxMessageSize), grpc.MaxSendMsgSize(pb.MaxMessageSize)}
+	if d.tlsConfig != nil {
+		if network != "tcp" {
+			return errors.New("TLS is only supported for tcp:// addresses")
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(d.tlsConfig)))
+	}
+	if network == "tcp" && transport.CheckListenAddress(d.address, d.tlsConfig) != nil {
+		d.log.Warningf("Daemon listens on %s without requiring client certificates, every client able to connect can execute arbitrary code on this host", d.address)
+	}
+	lis, err := transport.Listen(d.address)
+	if err != nil {
+		return err
+	}
+	d.server = grpc.NewServer(opts...)
 	pb.RegisterControlServer(d.server, d)
 	go func() {
-		d.log.Infof("Daemon listening on %s", d.socket)
+		d.log.Infof("Daemon listening on %s", d.address)
 		err = d.server.Serve(lis)
 		if err != nil {
 			d.log.Errorf("failed to start daemon: %v", err)
@@ -167,9 +201,13 @@ func (d *Daemon) shutdownSignalHandler(shutdown func(), shutdownLogstashInstance
 		if hardExit {
 			// Give a little time to propagate Done to kill context
 			time.Sleep(hardExitDelay)
-			err := os.Remove(d.socket)
+			network, socket, err := transport.Parse(d.address)
+			if err != nil || network != "unix" {
+				return
+			}
+			err = os.Remove(socket)
 			if err != nil && !os.IsNotExist(err) {
-				d.log.Warningf("failed to remove socket file %s during hard exit: %v", d.socket, err)
+				d.log.Warningf("failed to remove socket file %s during hard exit: %v", socket, err)
 			}
 		}
 	}()
@@ -285,6 +323,37 @@ func (d *Daemon) Shutdown(ctx context.Context, in *pb.ShutdownRequest) (*pb.Shut
 	return &pb.ShutdownResponse{}, nil
 }
 
+// Status returns information about the daemon, its Logstash instances and
+// the active test sessions.
+func (d *Daemon) Status(ctx context.Context, in *pb.StatusRequest) (*pb.StatusResponse, error) {
+	now := time.Now()
+	out := &pb.StatusResponse{
+		LogstashVersion: d.logstashVersion,
+		LogstashPath:    d.logstashPath,
+		PoolSize:        int32(d.poolSize),
+		UptimeSeconds:   int64(now.Sub(d.started).Seconds()),
+	}
+
+	for _, controller := range d.pool.Status() {
+		out.LogstashInstances = append(out.LogstashInstances, &pb.LogstashInstanceStatus{
+			Id:       controller.ID,
+			State:    controller.State,
+			Assigned: controller.Assigned,
+		})
+	}
+
+	for _, info := range d.sessionController.Sessions() {
+		out.Sessions = append(out.Sessions, &pb.SessionStatus{
+			Id:                 info.ID,
+			AgeSeconds:         int64(now.Sub(info.Created).Seconds()),
+			TestExecutions:     int32(info.TestExecutions),
+			LogstashInstanceID: info.LogstashControllerID,
+		})
+	}
+
+	return out, nil
+}
+
 // SetupTest creates a new session, receives the pipeline configuration
 // (zip archive), and prepares the files for the new session.
 func (d *Daemon) SetupTest(ctx context.Context, in *pb.SetupTestRequest) (*pb.SetupTestResponse, error) {
@@ -303,6 +372,7 @@ func (d *Daemon) SetupTest(ctx context.Context, in *pb.SetupTestRequest) (*pb.Se
 	if err != nil {
 		return nil, err
 	}
+	session.SetKeepOnFailure(in.KeepOnFailure)
 
 	return &pb.SetupTestResponse{
 		SessionID: session.ID(),
@@ -317,6 +387,7 @@ func (d *Daemon) extractZip(in []byte) (pipeline.Pipelines, []logstashconfig.Fil
 
 	pipelines := pipeline.Pipelines{}
 	configFiles := make([]logstashconfig.File, 0, len(r.File))
+	sourceMaps := map[string]logstashconfig.SourceMap{}
 	for _, f := range r.File {
 		err = func() (err error) {
 			rc, err := f.Open()
@@ -341,6 +412,11 @@ func (d *Daemon) extractZip(in []byte) (pipeline.Pipelines, []logstashconfig.Fil
 				if err != nil {
 					return err
 				}
+			case pipeline.SourceMapFile:
+				err = json.Unmarshal(body, &sourceMaps)
+				if err != nil {
+					return errors.Wrap(err, "invalid source map")
+				}
 			default:
 				configFile := logstashconfig.File{
 					Name: f.Name,
@@ -355,6 +431,12 @@ func (d *Daemon) extractZip(in []byte) (pipeline.Pipelines, []logstashconfig.Fil
 		}
 	}
 
+	for i := range configFiles {
+		if sourceMap, ok := sourceMaps[configFiles[i].Name]; ok {
+			configFiles[i].SourceMap = sourceMap
+		}
+	}
+
 	return pipelines, configFiles, nil
 }
 
@@ -368,7 +450,7 @@ func (d *Daemon) ExecuteTest(ctx context.Context, in *pb.ExecuteTestRequest) (ou
 
 	defer func() {
 		if err != nil {
-			d.sessionController.DestroyByID(in.SessionID)
+			d.destroyFailed(session)
 		}
 	}()
 
@@ -384,6 +466,9 @@ func (d *Daemon) ExecuteTest(ctx context.Context, in *pb.ExecuteTestRequest) (ou
 	}
 
 	results, err := session.GetResults()
+	if errors.As(err, &pipeline.LoadError{}) {
+		return nil, err
+	}
 	if err != nil {
 		d.log.Errorf("failed to wait for Logstash results: %v", err)
 	}
@@ -393,15 +478,214 @@ func (d *Daemon) ExecuteTest(ctx context.Context, in *pb.ExecuteTestRequest) (ou
 	}, nil
 }
 
+// ExecuteTestStream runs a test case set against the Logstash configuration
+// like ExecuteTest, but sends the events and the Logstash log lines with
+// level WARN or above to the client as soon as they arrive. If Logstash
+// fails to deliver all the results, the stream ends with an error.
+func (d *Daemon) ExecuteTestStream(in *pb.ExecuteTestRequest, stream pb.Control_ExecuteTestStreamServer) (err error) {
+	session, err := d.sessionController.Get(in.SessionID)
+	if err != nil {
+		return errors.Wrap(err, "invalid session ID")
+	}
+
+	defer func() {
+		if err != nil {
+			d.destroyFailed(session)
+		}
+	}()
+
+	events := []map[string]interface{}{}
+	err = json.Unmarshal(in.Events, &events)
+	if err != nil {
+		return errors.Wrap(err, "invalid json for fields")
+	}
+
+	err = session.ExecuteTest(in.InputPlugin, in.InputLines, events, int(in.ExpectedEvents))
+	if err != nil {
+		return err
+	}
+
+	var sendErr error
+	err = session.StreamResults(
+		func(event string) error {
+			sendErr = stream.Send(&pb.ExecuteTestStreamResponse{
+				Result: &pb.ExecuteTestStreamResponse_Event{Event: event},
+			})
+			return sendErr
+		},
+		func(line string) error {
+			sendErr = stream.Send(&pb.ExecuteTestStreamResponse{
+				Result: &pb.ExecuteTestStreamResponse_Log{Log: line},
+			})
+			return sendErr
+		},
+	)
+	if sendErr != nil {
+		return sendErr
+	}
+	if errors.As(err, &pipeline.LoadError{}) {
+		return err
+	}
+	if err != nil {
+		// Unlike ExecuteTest, the stream is ended with the error, such that
+		// the client does not compare the incomplete results.
+		return errors.Wrap(err, "failed to wait for Logstash results")
+	}
+
+	return nil
+}
+
+// ExecuteTestBatch runs multiple test case sets against the Logstash
+// configuration, that has been loaded previously with SetupTest. All the
+// test case sets are executed with a single reload of the Logstash config.
+// The Logstash log lines reporting dropped events are returned with the
+// test case set, the event belongs to, all the other log lines with level
+// WARN or above are returned for the whole batch.
+func (d *Daemon) ExecuteTestBatch(ctx context.Context, in *pb.ExecuteTestBatchRequest) (out *pb.ExecuteTestBatchResponse, err error) {
+	testSession, err := d.sessionController.Get(in.SessionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid session ID")
+	}
+
+	defer func() {
+		if err != nil {
+			d.destroyFailed(testSession)
+		}
+	}()
+
+	tests := make([]session.BatchTest, 0, len(in.TestCaseSets))
+	for _, t := range in.TestCaseSets {
+		events := []map[string]interface{}{}
+		err = json.Unmarshal(t.Events, &events)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid json for fields")
+		}
+		tests = append(tests, session.BatchTest{
+			InputPlugin: t.InputPlugin,
+			InputLines:  t.InputLines,
+			InEvents:    events,
+		})
+	}
+
+	err = testSession.ExecuteTestBatch(tests, int(in.ExpectedEvents))
+	if err != nil {
+		return nil, err
+	}
+
+	var results, logs []string
+	err = testSession.StreamResults(
+		func(event string) error {
+			results = append(results, event)
+			return nil
+		},
+		func(line string) error {
+			logs = append(logs, line)
+			return nil
+		},
+	)
+	if errors.As(err, &pipeline.LoadError{}) {
+		return nil, err
+	}
+	if err != nil {
+		d.log.Errorf("failed to wait for Logstash results: %v", err)
+	}
+
+	split, err := session.SplitResults(results, tests)
+	if err != nil {
+		return nil, err
+	}
+	splitLogs, remainingLogs := session.SplitLogs(logs, tests)
+
+	out = &pb.ExecuteTestBatchResponse{
+		Results: make([]*pb.TestCaseSetResults, 0, len(split)),
+		Logs:    remainingLogs,
+	}
+	for i, r := range split {
+		out.Results = append(out.Results, &pb.TestCaseSetResults{
+			Results: r,
+			Logs:    splitLogs[i],
+		})
+	}
+	return out, nil
+}
+
+// destroyFailed destroys the session after a failed test execution, unless
+// the client requested to keep it (see SetupTestRequest.KeepOnFailure).
+func (d *Daemon) destroyFailed(s *session.Session) {
+	if s.KeepOnFailure() {
+		return
+	}
+	err := d.sessionController.DestroyByID(s.ID())
+	if err != nil {
+		d.log.Warningf("session %s: %v", s.ID(), err)
+	}
+}
+
 // TeardownTest closes a test session, previously opened by SetupTest.
 // After all test case sets are executed against the Logstash configuration,
-// the test session needs to be closed.
+// the test session needs to be closed. If a test case set failed, the test
+// session has already been destroyed, unless the client requested to keep
+// it to dump its Logstash configuration.
+// If requested, the statistics of the session are returned.
 func (d *Daemon) TeardownTest(ctx context.Context, in *pb.TeardownTestRequest) (*pb.TeardownTestResponse, error) {
+	result := pb.TeardownTestResponse{}
+
+	if in.Stats || in.Config {
+		testSession, err := d.sessionController.Get(in.SessionID)
+		if err != nil {
+			return nil, err
+		}
+
+		if in.Stats {
+			stats, err := testSession.Stats()
+			if err != nil {
+				d.log.Warningf("session %s: %v", in.SessionID, err)
+			}
+			result.Stats = sessionStats(stats)
+		}
+
+		// The config is dumped before the session is destroyed, since the
+		// teardown removes the session directory and resets pipelines.yml.
+		if in.Config {
+			result.Config, err = testSession.DumpConfig()
+			if err != nil {
+				d.log.Warningf("session %s: %v", in.SessionID, err)
+			}
+		}
+	}
+
 	err := d.sessionController.DestroyByID(in.SessionID)
 	if err != nil {
 		return nil, errors.Wrap(err, "destroy of session failed")
 	}
 
-	result := pb.TeardownTestResponse{}
 	return &result, err
 }
+
+func sessionStats(stats session.Stats) *pb.SessionStats {
+	out := &pb.SessionStats{
+		TestExecutions: int32(stats.TestExecutions),
+		EventsIn:       int64(stats.EventsIn),
+		EventsOut:      int64(stats.EventsOut),
+	}
+	for _, execution := range stats.Executions {
+		out.TestExecutionStats = append(out.TestExecutionStats, &pb.TestExecutionStats{
+			EventsIn:         int64(execution.EventsIn),
+			EventsOut:        int64(execution.EventsOut),
+			ReloadMillis:     execution.Reload.Milliseconds(),
+			ProcessingMillis: execution.Processing.Milliseconds(),
+		})
+	}
+	for _, plugin := range stats.Plugins {
+		out.PluginStats = append(out.PluginStats, &pb.PluginStats{
+			PipelineID:     plugin.PipelineID,
+			Id:             plugin.ID,
+			Name:           plugin.Name,
+			Kind:           plugin.Kind,
+			EventsIn:       plugin.EventsIn,
+			EventsOut:      plugin.EventsOut,
+			DurationMillis: plugin.Duration.Milliseconds(),
+		})
+	}
+	return out
+}
@@ -0,0 +1,56 @@
+package run
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/logstashconfig"
+)
+
+// drop is an input event, which has been dropped by a drop filter.
+type drop struct {
+	event  int
+	plugin string
+	file   string
+	branch string
+}
+
+// parseDrop returns the dropped event reported by a Logstash log line.
+// ok is false, if the log line does not report a dropped event.
+func parseDrop(log string) (d drop, ok bool) {
+	logEvent := gjson.Get(log, "logEvent")
+	if logEvent.Get("message").String() != logstashconfig.DroppedMessage {
+		return drop{}, false
+	}
+
+	return drop{
+		event:  int(logEvent.Get("event").Int()),
+		plugin: logEvent.Get("id").String(),
+		file:   logEvent.Get("file").String(),
+		branch: logEvent.Get("branch").String(),
+	}, true
+}
+
+func (d drop) String() string {
+	s := fmt.Sprintf("event %d dropped by plugin `%s` in `%s`", d.event+1, d.plugin, d.file)
+	if d.branch != "" {
+		s += fmt.Sprintf(" (%s)", d.branch)
+	}
+	return s
+}
+
+// renderDrops returns the descriptions of the dropped events ordered by
+// the input event.
+func renderDrops(drops []drop) []string {
+	sort.SliceStable(drops, func(i, j int) bool {
+		return drops[i].event < drops[j].event
+	})
+
+	var result []string
+	for _, d := range drops {
+		result = append(result, d.String())
+	}
+	return result
+}
@@ -0,0 +1,63 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseDrop(t *testing.T) {
+	cases := []struct {
+		name string
+		log  string
+
+		wantOK bool
+		want   string
+	}{
+		{
+			name: "dropped event within branch",
+			log:  `{"level":"WARN","loggerName":"logstash.filters.ruby","logEvent":{"message":"__lfv_dropped","id":"drop_healthchecks","file":"filters/10-web.conf","branch":"if [path] == \"/health\"","event":"2"}}`,
+
+			wantOK: true,
+			want:   "event 3 dropped by plugin `drop_healthchecks` in `filters/10-web.conf` (if [path] == \"/health\")",
+		},
+		{
+			name: "dropped event outside of branch",
+			log:  `{"level":"WARN","logEvent":{"message":"__lfv_dropped","id":"drop_all","file":"main.conf","branch":"","event":"0"}}`,
+
+			wantOK: true,
+			want:   "event 1 dropped by plugin `drop_all` in `main.conf`",
+		},
+		{
+			name: "other log line",
+			log:  `{"level":"WARN","logEvent":{"message":"Could not index event"}}`,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			d, ok := parseDrop(test.log)
+			is.Equal(ok, test.wantOK)
+			if !ok {
+				return
+			}
+			is.Equal(d.String(), test.want)
+		})
+	}
+}
+
+func TestRenderDrops(t *testing.T) {
+	is := is.New(t)
+
+	got := renderDrops([]drop{
+		{event: 2, plugin: "b", file: "main.conf"},
+		{event: 0, plugin: "a", file: "main.conf"},
+	})
+
+	is.Equal(got, []string{
+		"event 1 dropped by plugin `a` in `main.conf`",
+		"event 3 dropped by plugin `b` in `main.conf`",
+	})
+}
@@ -0,0 +1,113 @@
+package run
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pipeline"
+)
+
+// setupFailedDir is the sub directory of the config dump directory, where
+// the Logstash config sent to the daemon is written to, if the test session
+// could not be set up.
+const setupFailedDir = "setup-failed"
+
+// dumpMutex serializes the config dumps of test sessions executed in
+// parallel.
+var dumpMutex sync.Mutex
+
+// dumpSessionConfig writes the Logstash configuration loaded by the daemon
+// for the test session to a sub directory of s.dumpConfig.
+func (s Test) dumpSessionConfig(sessionID string, archive []byte) error {
+	if len(archive) == 0 {
+		s.log.Warningf("The Logstash configuration of session %s is not available", sessionID)
+		return nil
+	}
+	dir := filepath.Join(s.dumpConfig, sessionID)
+	err := writeConfigDump(dir, archive)
+	if err != nil {
+		return errors.Wrapf(err, "failed to dump the Logstash configuration of session %s", sessionID)
+	}
+	s.log.Infof("Logstash configuration of session %s written to %s", sessionID, dir)
+	return nil
+}
+
+// dumpSetupFailure writes the Logstash config sent to the daemon, if the
+// test session could not be set up and therefore the Logstash configuration
+// of the session is not available.
+func (s Test) dumpSetupFailure(archive []byte) {
+	if s.dumpConfig == "" {
+		return
+	}
+
+	dumpMutex.Lock()
+	defer dumpMutex.Unlock()
+
+	dir := filepath.Join(s.dumpConfig, setupFailedDir)
+	err := os.RemoveAll(dir)
+	if err == nil {
+		err = writeConfigDump(dir, archive)
+	}
+	if err != nil {
+		s.log.Warningf("Failed to dump the Logstash configuration sent to the daemon: %s", err)
+		return
+	}
+	s.log.Infof("The test session could not be set up, the Logstash configuration sent to the daemon is written to %s", dir)
+}
+
+// writeConfigDump extracts the zip archive with the Logstash configuration
+// of a test session into dir.
+func writeConfigDump(dir string, archive []byte) error {
+	r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range r.File {
+		// The source maps are only used by the daemon.
+		if file.Name == pipeline.SourceMapFile {
+			continue
+		}
+
+		// The names of the Logstash config files sent to the daemon start
+		// with a slash.
+		name := filepath.FromSlash(strings.TrimPrefix(file.Name, "/"))
+		if !filepath.IsLocal(name) {
+			return errors.Errorf("invalid file name %q in config dump", file.Name)
+		}
+
+		err = extractFile(file, filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func extractFile(file *zip.File, path string) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	body, err := io.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, body, 0644)
+}
@@ -0,0 +1,89 @@
+package run
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestWriteConfigDump(t *testing.T) {
+	cases := []struct {
+		name  string
+		files map[string]string
+
+		wantErr bool
+		want    map[string]string
+	}{
+		{
+			name: "session files",
+			files: map[string]string{
+				"sut/main.conf":           "filter {}",
+				"lfv_inputs/1/input.conf": "input {}",
+				"pipelines.yml":           "- pipeline.id: stdin",
+			},
+
+			want: map[string]string{
+				"sut/main.conf":           "filter {}",
+				"lfv_inputs/1/input.conf": "input {}",
+				"pipelines.yml":           "- pipeline.id: stdin",
+			},
+		},
+		{
+			name: "config sent to the daemon",
+			files: map[string]string{
+				"pipelines.yml":        "- pipeline.id: main",
+				"/folder/main.conf":    "filter {}",
+				"__lfv_sourcemap.json": "{}",
+			},
+
+			want: map[string]string{
+				"pipelines.yml":    "- pipeline.id: main",
+				"folder/main.conf": "filter {}",
+			},
+		},
+		{
+			name: "file outside of the directory",
+			files: map[string]string{
+				"../main.conf": "filter {}",
+			},
+
+			wantErr: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			buf := new(bytes.Buffer)
+			w := zip.NewWriter(buf)
+			for name, body := range test.files {
+				f, err := w.Create(name)
+				is.NoErr(err)
+				_, err = f.Write([]byte(body))
+				is.NoErr(err)
+			}
+			is.NoErr(w.Close())
+
+			dir := filepath.Join(t.TempDir(), "session")
+			err := writeConfigDump(dir, buf.Bytes())
+			is.Equal(err != nil, test.wantErr)
+			if test.wantErr {
+				return
+			}
+
+			for name, body := range test.want {
+				got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+				is.NoErr(err)
+				is.Equal(string(got), body)
+			}
+
+			_, err = os.Stat(filepath.Join(dir, "__lfv_sourcemap.json"))
+			is.True(os.IsNotExist(err)) // source map is not written
+		})
+	}
+}
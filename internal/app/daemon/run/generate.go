@@ -0,0 +1,125 @@
+package run
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logstash"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/testcase"
+)
+
+type generatedTestCaseSet struct {
+	InputPlugin string              `yaml:"input_plugin"`
+	Ignore      []string            `yaml:"ignore,omitempty"`
+	TestCases   []generatedTestCase `yaml:"testcases"`
+}
+
+type generatedTestCase struct {
+	Description string   `yaml:"description"`
+	InputLines  []string `yaml:"input"`
+}
+
+// Generate creates the test case file output with a test case for each
+// line in the file inputFile. The lines are processed by the Logstash
+// config, starting at the input plugin with the ID inputPlugin. The events
+// emitted by Logstash become the expected events of the respective test
+// case. The fields in ignore are removed from the events and are listed in
+// the ignore section of the test case file. An existing file output is only
+// overwritten, if force is set. output is only written, if Logstash emitted
+// at least one event.
+func (s Test) Generate(inputPlugin string, inputFile string, ignore []string, output string, force bool) error {
+	if !force {
+		_, err := os.Stat(output)
+		if err == nil {
+			return fmt.Errorf("test case file %s already exists, use --force to overwrite it", output)
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	lines, err := readLines(inputFile)
+	if err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		return fmt.Errorf("input file %s does not contain any lines", inputFile)
+	}
+
+	skeleton := generatedTestCaseSet{
+		InputPlugin: inputPlugin,
+		Ignore:      ignore,
+	}
+	for i, line := range lines {
+		skeleton.TestCases = append(skeleton.TestCases, generatedTestCase{
+			Description: fmt.Sprintf("%s line %d", filepath.Base(inputFile), i+1),
+			InputLines:  []string{line},
+		})
+	}
+
+	body, err := yaml.Marshal(skeleton)
+	if err != nil {
+		return err
+	}
+
+	// The test case file is built in a temporary file next to output, which
+	// replaces output only after the expected events have been written.
+	tmp, err := os.CreateTemp(filepath.Dir(output), ".lfv-generate-*.yml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	t, err := testcase.NewFromFile(tmp.Name())
+	if err != nil {
+		return err
+	}
+
+	err = s.execute([]testcase.TestCaseSet{*t}, true, func(t testcase.TestCaseSet, events []logstash.Event, inputIDs []int, _ []string) error {
+		s.log.Infof("Received %d events for %d input lines", len(events), len(lines))
+		if len(events) == 0 {
+			return fmt.Errorf("no events received from Logstash for the input plugin %s", inputPlugin)
+		}
+		return t.UpdateExpected(events, inputIDs)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tmp.Name(), 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), output)
+}
+
+// readLines returns the non-empty lines of the file.
+func readLines(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			continue
+		}
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,35 @@
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
+)
+
+func TestGenerateExistingOutput(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	output := filepath.Join(dir, "testcase.yml")
+	err := os.WriteFile(output, []byte("existing"), 0600)
+	is.NoErr(err)
+	inputFile := filepath.Join(dir, "sample.log")
+	err = os.WriteFile(inputFile, []byte("line\n"), 0600)
+	is.NoErr(err)
+
+	s := Test{log: logging.NoopLogger}
+	err = s.Generate("input", inputFile, nil, output, false)
+	is.True(err != nil) // existing test case file is not overwritten
+
+	body, err := os.ReadFile(output)
+	is.NoErr(err)
+	is.Equal(string(body), "existing")
+
+	entries, err := os.ReadDir(dir)
+	is.NoErr(err)
+	is.Equal(len(entries), 2) // no temporary file left behind
+}
@@ -2,14 +2,15 @@ package run
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"net"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
-	"time"
+	"sync"
 
 	"github.com/breml/logstash-config/ast"
 	"github.com/breml/logstash-config/ast/astutil"
@@ -20,9 +21,11 @@ import (
 	"google.golang.org/grpc"
 	"gopkg.in/yaml.v2"
 
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/coverage"
 	pb "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/api/grpc"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pipeline"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pluginmock"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/transport"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logstash"
 	lfvobserver "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/observer"
@@ -30,7 +33,8 @@ import (
 )
 
 type Test struct {
-	socket         string
+	address        string
+	tlsConfig      *tls.Config
 	pipeline       string
 	pipelineBase   string
 	logstashConfig string
@@ -39,13 +43,88 @@ type Test struct {
 	metadataKey    string
 	debug          bool
 	addMissingID   bool
+	update         bool
+	diffCommand    []string
+	reports        []string
+	outputFormat   string
+	include        []string
+	exclude        []string
+	filter         testcase.Filter
+	parallel       int
+	batch          bool
+	stats          bool
+	trace          bool
+	reportDrops    bool
+	dumpConfig     string
+
+	coverageReports []coverage.Report
+	coverage        *coverage.Coverage
+
+	// collectedStats collects the statistics of the test sessions, if
+	// stats is set.
+	collectedStats *statsCollector
 
 	log logging.Logger
 }
 
-func New(socket string, log logging.Logger, pipeline, pipelineBase, logstashConfig, testcasePath, pluginMock, metadataKey string, debug, addMissingID bool) (Test, error) {
+// Options contains the settings of a test run.
+type Options struct {
+	// Address is the address of the daemon, either tcp://host:port or the
+	// path of the control socket.
+	Address string
+	// TLSConfig, if not nil, secures the connection to the daemon with TLS.
+	TLSConfig *tls.Config
+
+	Pipeline       string
+	PipelineBase   string
+	LogstashConfig string
+	TestcasePath   string
+	PluginMock     string
+	MetadataKey    string
+	Debug          bool
+	AddMissingID   bool
+	Update         bool
+	DiffCommand    []string
+	Reports        []string
+	OutputFormat   string
+	Include        []string
+	Exclude        []string
+	Filter         testcase.Filter
+
+	// Parallel is the number of test sessions to execute the test case
+	// sets in parallel.
+	Parallel int
+	// Batch executes the test case sets of a test session with a single
+	// reload of the Logstash config.
+	Batch bool
+	// Stats prints the statistics of each test session after its teardown.
+	Stats bool
+	// CoverageReports are the definitions (<format>[:<path>]) of the
+	// coverage reports written after the test run, if empty, no coverage is
+	// collected.
+	CoverageReports []string
+	// Trace records the changes of each filter to the events and shows
+	// them for the failed test cases.
+	Trace bool
+	// ReportDrops reports the events dropped by drop filters for the failed
+	// test cases.
+	ReportDrops bool
+	// DumpConfig, if not empty, is the directory, where the Logstash
+	// configuration loaded by the daemon for each test session is written
+	// to, in a sub directory named after the session.
+	DumpConfig string
+}
+
+// New creates a test run with the given options.
+func New(opts Options, log logging.Logger) (Test, error) {
+	parsedCoverageReports, err := coverage.ParseReports(opts.CoverageReports)
+	if err != nil {
+		return Test{}, err
+	}
+
+	pipelineBase := opts.PipelineBase
 	if pipelineBase == "" {
-		absPipeline, err := filepath.Abs(pipeline)
+		absPipeline, err := filepath.Abs(opts.Pipeline)
 		if err != nil {
 			return Test{}, err
 		}
@@ -59,161 +138,630 @@ func New(socket string, log logging.Logger, pipeline, pipelineBase, logstashConf
 		pipelineBase = filepath.Join(cwd, pipelineBase)
 	}
 	return Test{
-		socket:         socket,
-		pipeline:       pipeline,
+		address:        opts.Address,
+		tlsConfig:      opts.TLSConfig,
+		pipeline:       opts.Pipeline,
 		pipelineBase:   pipelineBase,
-		logstashConfig: logstashConfig,
-		testcasePath:   testcasePath,
-		pluginMock:     pluginMock,
-		metadataKey:    metadataKey,
-		debug:          debug,
-		addMissingID:   addMissingID,
-		log:            log,
+		logstashConfig: opts.LogstashConfig,
+		testcasePath:   opts.TestcasePath,
+		pluginMock:     opts.PluginMock,
+		metadataKey:    opts.MetadataKey,
+		debug:          opts.Debug,
+		addMissingID:   opts.AddMissingID,
+		update:         opts.Update,
+		diffCommand:    opts.DiffCommand,
+		reports:        opts.Reports,
+		outputFormat:   opts.OutputFormat,
+		include:        opts.Include,
+		exclude:        opts.Exclude,
+		filter:         opts.Filter,
+		parallel:       opts.Parallel,
+		batch:          opts.Batch,
+		stats:          opts.Stats,
+		trace:          opts.Trace,
+		reportDrops:    opts.ReportDrops,
+		dumpConfig:     opts.DumpConfig,
+
+		coverageReports: parsedCoverageReports,
+		collectedStats:  &statsCollector{},
+
+		log: log,
 	}, nil
 }
 
 func (s Test) Run() (err error) {
-	if s.logstashConfig != "" {
-		pipelineFile, err := s.createImplicitPipeline()
-		if err != nil {
-			return err
+	// The statistics are printed after the test results.
+	defer func() {
+		if statsErr := s.printStats(os.Stdout); statsErr != nil && err == nil {
+			err = statsErr
 		}
-		defer os.RemoveAll(filepath.Dir(pipelineFile))
+	}()
 
-		s.pipeline = pipelineFile
-		s.pipelineBase = ""
+	tests, err := s.discoverTests()
+	if err != nil {
+		return err
+	}
+	if len(tests) == 0 {
+		s.log.Warning("No test cases selected")
+		return nil
 	}
 
-	a, err := pipeline.New(s.pipeline, s.pipelineBase)
+	liveObserver, observers, err := s.startObservers()
 	if err != nil {
 		return err
 	}
 
-	m, err := pluginmock.FromFile(s.pluginMock)
+	if len(s.coverageReports) > 0 {
+		s.coverage = coverage.New()
+	}
+
+	// With --update, the number of events may differ from the expected
+	// events, so the daemon waits for the events to stop arriving.
+	testsPassed := true
+	err = s.execute(tests, s.update, s.compareResults(liveObserver, &testsPassed))
 	if err != nil {
 		return err
 	}
 
-	preprocessor := pipeline.NoopPreprocessor
-	if len(m) > 0 {
-		preprocessor = pipeline.ApplyMocksPreprocessor(m)
+	if s.coverage != nil {
+		err = s.coverage.WriteReports(s.coverageReports)
+		if err != nil {
+			return err
+		}
 	}
 
-	// TODO: ensure, that IDs are also unique for the whole set of pipelines
-	b, inputs, err := a.ZipWithPreprocessor(s.addMissingID, preprocessor)
+	liveObserver.Update(lfvobserver.TestExecutionEnd{})
+
+	for _, obs := range observers {
+		if err := obs.Finalize(); err != nil {
+			return err
+		}
+	}
+
+	if !testsPassed {
+		return errors.New("failed test cases")
+	}
+
+	return nil
+}
+
+// discoverTests returns the test case sets selected by the include and
+// exclude patterns and the filter.
+func (s Test) discoverTests() ([]testcase.TestCaseSet, error) {
+	tests, err := testcase.DiscoverTests(s.testcasePath, s.include, s.exclude)
+	if err != nil {
+		return nil, err
+	}
+	return s.filter.Apply(tests)
+}
+
+func (s Test) startObservers() (observer.Property, []lfvobserver.Interface, error) {
+	observers := make([]lfvobserver.Interface, 0)
+	liveObserver := observer.NewProperty(lfvobserver.TestExecutionStart{})
+	outputObserver, err := lfvobserver.NewOutputObserver(liveObserver, s.outputFormat)
+	if err != nil {
+		return nil, nil, err
+	}
+	observers = append(observers, outputObserver)
+	reportObservers, err := lfvobserver.NewReportObservers(liveObserver, s.reports)
+	if err != nil {
+		return nil, nil, err
+	}
+	observers = append(observers, reportObservers...)
+	for _, obs := range observers {
+		if err := obs.Start(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return liveObserver, observers, nil
+}
+
+// compareResults returns a resultHandler, which compares the events with
+// the expected events and updates the test case files, if requested.
+// testsPassed is set to false, if a test case set fails.
+func (s Test) compareResults(liveObserver observer.Property, testsPassed *bool) resultHandler {
+	return func(t testcase.TestCaseSet, events []logstash.Event, inputIDs []int, dropped []string) error {
+		details := testcase.Details{
+			Traces:  extractTraces(events),
+			Dropped: dropped,
+		}
+		ok, err := t.CompareWithDetails(events, details, s.diffCommand, liveObserver)
+		if err != nil {
+			return err
+		}
+		if !ok && s.update {
+			if err = t.UpdateExpected(events, inputIDs); err != nil {
+				return err
+			}
+			s.log.Infof("Updated expected events in %s", t.Name())
+			ok = true
+		}
+		if !ok {
+			*testsPassed = false
+		}
+		return nil
+	}
+}
+
+// resultHandler processes the events emitted by Logstash for a test case
+// set. inputIDs contains for each event the index of the input line, the
+// event originates from. dropped describes the input events, which have
+// been dropped by a drop filter.
+type resultHandler func(t testcase.TestCaseSet, events []logstash.Event, inputIDs []int, dropped []string) error
+
+// execute sets up a test session with the daemon, executes each of the
+// test case sets and passes the resulting events to handle. If
+// unknownExpected is set, the number of expected events is not passed to
+// the daemon, instead the daemon waits for the events to stop arriving.
+func (s Test) execute(tests []testcase.TestCaseSet, unknownExpected bool, handle resultHandler) (err error) {
+	b, inputs, err := s.zipPipeline()
 	if err != nil {
 		return err
 	}
 
-	tests, err := testcase.DiscoverTests(s.testcasePath)
+	err = checkInputPlugins(tests, inputs)
 	if err != nil {
 		return err
 	}
-	for _, test := range tests {
-		if _, ok := inputs[test.InputPlugin]; !ok {
-			return errors.Errorf("input plugin %q defined in test case but not present in Logstash config", test.InputPlugin)
+
+	if s.coverage != nil {
+		for _, t := range tests {
+			s.coverage.AddInput(t.InputPlugin, int64(len(t.InputLines)))
 		}
 	}
 
-	s.log.Debugf("socket to daemon %q", s.socket)
-	conn, err := grpc.Dial(
-		s.socket,
-		grpc.WithInsecure(), //nolint:staticcheck
-		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
-			if d, ok := ctx.Deadline(); ok {
-				return net.DialTimeout("unix", addr, time.Until(d))
-			}
-			return net.Dial("unix", addr)
-		}))
+	conn, err := s.dial()
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
 	c := pb.NewControlClient(conn)
 
-	result, err := c.SetupTest(context.Background(), &pb.SetupTestRequest{
-		Pipeline: b,
-	})
+	if s.parallel > 1 && len(tests) > 1 {
+		return s.executeParallel(c, b, tests, unknownExpected, handle)
+	}
+
+	sessionID, err := s.setupTest(c, b)
 	if err != nil {
+		s.dumpSetupFailure(b)
 		return err
 	}
-	sessionID := result.SessionID
 
 	defer func() {
-		_, teardownErr := c.TeardownTest(context.Background(), &pb.TeardownTestRequest{
-			SessionID: sessionID,
-			Stats:     false,
-		})
+		teardownErr := s.teardownTest(c, sessionID)
 		if teardownErr != nil {
 			err = fmt.Errorf("failed to teardown connection: %v, root cause: %v", teardownErr, err)
 		}
 	}()
 
-	observers := make([]lfvobserver.Interface, 0)
-	liveObserver := observer.NewProperty(lfvobserver.TestExecutionStart{})
-	observers = append(observers, lfvobserver.NewSummaryObserver(liveObserver))
-	for _, obs := range observers {
-		if err := obs.Start(); err != nil {
+	return s.executeTests(c, sessionID, tests, unknownExpected, handle)
+}
+
+// zipPipeline returns the Logstash config with the plugin mocks applied as
+// zip archive together with the number of occurrences of each input plugin
+// ID.
+func (s Test) zipPipeline() ([]byte, map[string]int, error) {
+	if s.logstashConfig != "" {
+		pipelineFile, err := s.createImplicitPipeline()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer os.RemoveAll(filepath.Dir(pipelineFile))
+
+		s.pipeline = pipelineFile
+		s.pipelineBase = ""
+	}
+
+	a, err := pipeline.New(s.pipeline, s.pipelineBase)
+	if err != nil {
+		return nil, nil, err
+	}
+	a.Coverage = s.coverage
+	a.Trace = s.trace
+	a.Drops = s.reportDrops
+
+	m, err := pluginmock.FromFile(s.pluginMock)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	preprocessor := pipeline.NoopPreprocessor
+	if len(m) > 0 {
+		preprocessor = pipeline.ApplyMocksPreprocessor(m)
+	}
+
+	// TODO: ensure, that IDs are also unique for the whole set of pipelines
+	return a.ZipWithPreprocessor(s.addMissingID, preprocessor)
+}
+
+func checkInputPlugins(tests []testcase.TestCaseSet, inputs map[string]int) error {
+	for _, test := range tests {
+		if _, ok := inputs[test.InputPlugin]; !ok {
+			return errors.Errorf("input plugin %q defined in test case but not present in Logstash config", test.InputPlugin)
+		}
+	}
+	return nil
+}
+
+func (s Test) dial() (*grpc.ClientConn, error) {
+	s.log.Debugf("address of daemon %q", s.address)
+	return transport.Dial(
+		s.address,
+		s.tlsConfig,
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(pb.MaxMessageSize), grpc.MaxCallSendMsgSize(pb.MaxMessageSize)),
+	)
+}
+
+func (s Test) setupTest(c pb.ControlClient, pipeline []byte) (string, error) {
+	result, err := c.SetupTest(context.Background(), &pb.SetupTestRequest{
+		Pipeline: pipeline,
+		// The session is required after a failure to dump its config.
+		KeepOnFailure: s.dumpConfig != "",
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.SessionID, nil
+}
+
+// teardownTest closes the test session. If s.stats is set, the statistics
+// of the session are collected to be printed by printStats. If s.dumpConfig is set, the Logstash
+// configuration of the session is written to s.dumpConfig.
+func (s Test) teardownTest(c pb.ControlClient, sessionID string) error {
+	result, err := c.TeardownTest(context.Background(), &pb.TeardownTestRequest{
+		SessionID: sessionID,
+		Stats:     s.stats || s.coverage != nil,
+		Config:    s.dumpConfig != "",
+	})
+	if err != nil {
+		return err
+	}
+	if s.dumpConfig != "" {
+		err = s.dumpSessionConfig(sessionID, result.Config)
+		if err != nil {
 			return err
 		}
 	}
+	if result.Stats == nil {
+		return nil
+	}
+	if s.coverage != nil {
+		if len(result.Stats.PluginStats) == 0 {
+			s.log.Warning("Plugin statistics of Logstash are not available, the coverage of the filters is incomplete")
+		}
+		for _, plugin := range result.Stats.PluginStats {
+			s.coverage.AddPluginStats(plugin.Id, plugin.EventsIn, plugin.EventsOut)
+		}
+	}
+	if s.stats && s.collectedStats != nil {
+		s.collectedStats.add(sessionID, result.Stats)
+	}
+	return nil
+}
 
-	testsPassed := true
-	for _, t := range tests {
-		b, err := json.Marshal(t.Events)
+// executeTests executes each of the test case sets in the already set up
+// test session and passes the resulting events to handle. If s.batch is
+// set, all the test case sets are executed in a single batch.
+func (s Test) executeTests(c pb.ControlClient, sessionID string, tests []testcase.TestCaseSet, unknownExpected bool, handle resultHandler) error {
+	if s.batch {
+		results, err := s.executeBatch(c, sessionID, tests, unknownExpected)
 		if err != nil {
 			return err
 		}
-		s.validateInputLines(t.InputLines)
+		for i, t := range tests {
+			if results[i].err != nil {
+				return results[i].err
+			}
+			err = handle(t, results[i].events, results[i].inputIDs, results[i].dropped)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 
-		result, err := c.ExecuteTest(context.Background(), &pb.ExecuteTestRequest{
-			SessionID:      sessionID,
-			InputPlugin:    t.InputPlugin,
-			InputLines:     t.InputLines,
-			Events:         b,
-			ExpectedEvents: int32(len(t.ExpectedEvents)),
-		})
+	for _, t := range tests {
+		events, inputIDs, dropped, err := s.executeTest(c, sessionID, t, unknownExpected)
 		if err != nil {
 			return err
 		}
 
-		results, err := s.postProcessResults(result.Results, t)
+		err = handle(t, events, inputIDs, dropped)
 		if err != nil {
 			return err
 		}
+	}
 
-		var events []logstash.Event
-		for _, line := range results {
-			var event logstash.Event
-			err = json.Unmarshal([]byte(line), &event)
-			if err != nil {
-				return err
+	return nil
+}
+
+// testResult contains the outcome of the execution of a test case set.
+type testResult struct {
+	events   []logstash.Event
+	inputIDs []int
+	dropped  []string
+	err      error
+}
+
+// executeParallel executes the test case sets in s.parallel test sessions
+// in parallel. Each session picks the next test case set, as soon as it
+// is done with the previous one. If s.batch is set, the test case sets are
+// split evenly between the sessions instead and each session executes its
+// share in a single batch. The results are passed to handle in the order of
+// the test case sets.
+func (s Test) executeParallel(c pb.ControlClient, pipeline []byte, tests []testcase.TestCaseSet, unknownExpected bool, handle resultHandler) (err error) {
+	workers := s.parallel
+	if workers > len(tests) {
+		workers = len(tests)
+	}
+
+	chunkSize := 1
+	if s.batch {
+		chunkSize = (len(tests) + workers - 1) / workers
+	}
+	jobs := make(chan []int, len(tests))
+	for i := 0; i < len(tests); i += chunkSize {
+		chunk := []int{}
+		for j := i; j < i+chunkSize && j < len(tests); j++ {
+			chunk = append(chunk, j)
+		}
+		jobs <- chunk
+	}
+	close(jobs)
+
+	results := make([]chan testResult, len(tests))
+	for i := range results {
+		results[i] = make(chan testResult, 1)
+	}
+
+	done := make(chan struct{})
+	teardownErrs := make(chan error, workers)
+	wg := &sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			teardownErrs <- s.executeWorker(c, pipeline, tests, unknownExpected, jobs, results, done)
+		}()
+	}
+
+	defer func() {
+		close(done)
+		wg.Wait()
+		close(teardownErrs)
+		for teardownErr := range teardownErrs {
+			if teardownErr != nil {
+				err = fmt.Errorf("failed to teardown connection: %v, root cause: %v", teardownErr, err)
 			}
-			events = append(events, event)
+		}
+	}()
+
+	for i, t := range tests {
+		result := <-results[i]
+		if result.err != nil {
+			return result.err
 		}
 
-		ok, err := t.Compare(events, []string{"diff", "-u"}, liveObserver)
+		err = handle(t, result.events, result.inputIDs, result.dropped)
 		if err != nil {
 			return err
 		}
-		if !ok {
-			testsPassed = false
+	}
+
+	return nil
+}
+
+// executeWorker sets up a test session and executes the chunks of test
+// case sets received from queue until there are no more jobs or done is
+// closed. The result of each test case set is sent to the respective
+// results channel. After a failed test case set, the test session is not
+// used any more and the error is passed on as result of the remaining test
+// case sets. The error of the teardown of the test session is returned.
+func (s Test) executeWorker(c pb.ControlClient, pipeline []byte, tests []testcase.TestCaseSet, unknownExpected bool, queue <-chan []int, results []chan testResult, done <-chan struct{}) error {
+	sessionID, setupErr := s.setupTest(c, pipeline)
+	if setupErr != nil {
+		s.dumpSetupFailure(pipeline)
+	}
+	sessionErr := setupErr
+
+jobs:
+	for chunk := range queue {
+		select {
+		case <-done:
+			break jobs
+		default:
+		}
+
+		if sessionErr != nil {
+			for _, i := range chunk {
+				results[i] <- testResult{err: sessionErr}
+			}
+			continue
+		}
+
+		if s.batch {
+			chunkTests := make([]testcase.TestCaseSet, 0, len(chunk))
+			for _, i := range chunk {
+				chunkTests = append(chunkTests, tests[i])
+			}
+			chunkResults, err := s.executeBatch(c, sessionID, chunkTests, unknownExpected)
+			for j, i := range chunk {
+				if err != nil {
+					results[i] <- testResult{err: err}
+					continue
+				}
+				results[i] <- chunkResults[j]
+			}
+			sessionErr = err
+			continue
+		}
+
+		for _, i := range chunk {
+			result := testResult{err: sessionErr}
+			if sessionErr == nil {
+				result.events, result.inputIDs, result.dropped, result.err = s.executeTest(c, sessionID, tests[i], unknownExpected)
+				sessionErr = result.err
+			}
+			results[i] <- result
 		}
 	}
 
-	liveObserver.Update(lfvobserver.TestExecutionEnd{})
+	if setupErr != nil {
+		return nil
+	}
+	return s.teardownTest(c, sessionID)
+}
 
-	for _, obs := range observers {
-		if err := obs.Finalize(); err != nil {
-			return err
+// executeTest executes a test case set in the test session and returns
+// the resulting events together with the index of the input line, each
+// event originates from, and the description of the dropped input events.
+func (s Test) executeTest(c pb.ControlClient, sessionID string, t testcase.TestCaseSet, unknownExpected bool) ([]logstash.Event, []int, []string, error) {
+	b, err := json.Marshal(t.Events)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	s.validateInputLines(t.InputLines)
+
+	expectedEvents := int32(len(t.ExpectedEvents))
+	if unknownExpected {
+		expectedEvents = -1
+	}
+
+	stream, err := c.ExecuteTestStream(context.Background(), &pb.ExecuteTestRequest{
+		SessionID:      sessionID,
+		InputPlugin:    t.InputPlugin,
+		InputLines:     t.InputLines,
+		Events:         b,
+		ExpectedEvents: expectedEvents,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var results []string
+	var drops []drop
+	for {
+		result, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		switch r := result.Result.(type) {
+		case *pb.ExecuteTestStreamResponse_Event:
+			results = append(results, r.Event)
+			if expectedEvents < 0 {
+				s.log.Debugf("%s: received event %d", t.Name(), len(results))
+			} else {
+				s.log.Debugf("%s: received event %d of %d", t.Name(), len(results), expectedEvents)
+			}
+		case *pb.ExecuteTestStreamResponse_Log:
+			if d, ok := parseDrop(r.Log); ok {
+				s.log.Debugf("%s: %s", t.Name(), d)
+				drops = append(drops, d)
+				continue
+			}
+			s.log.Warningf("Logstash %s: %s", gjson.Get(r.Log, "level").String(), logMessage(r.Log))
 		}
 	}
 
-	if !testsPassed {
-		return errors.New("failed test cases")
+	events, inputIDs, err := s.parseResults(results, t)
+	if err != nil {
+		return nil, nil, nil, err
 	}
+	return events, inputIDs, renderDrops(drops), nil
+}
 
-	return nil
+// logMessage returns the message of a Logstash log line. Logstash logs the
+// details of some errors (e.g. compile errors of the Logstash config) as an
+// additional message, which is appended.
+func logMessage(line string) string {
+	var messages []string
+	gjson.Get(line, "logEvent").ForEach(func(key, value gjson.Result) bool {
+		if key.String() == "message" {
+			messages = append(messages, value.String())
+		}
+		return true
+	})
+	return strings.Join(messages, ": ")
+}
+
+// executeBatch executes the test case sets with a single reload of the
+// Logstash config in the test session and returns the result of each test
+// case set.
+func (s Test) executeBatch(c pb.ControlClient, sessionID string, tests []testcase.TestCaseSet, unknownExpected bool) ([]testResult, error) {
+	in := &pb.ExecuteTestBatchRequest{
+		SessionID:    sessionID,
+		TestCaseSets: make([]*pb.TestCaseSet, 0, len(tests)),
+	}
+	for _, t := range tests {
+		b, err := json.Marshal(t.Events)
+		if err != nil {
+			return nil, err
+		}
+		s.validateInputLines(t.InputLines)
+
+		in.TestCaseSets = append(in.TestCaseSets, &pb.TestCaseSet{
+			InputPlugin: t.InputPlugin,
+			InputLines:  t.InputLines,
+			Events:      b,
+		})
+		in.ExpectedEvents += int32(len(t.ExpectedEvents))
+	}
+	if unknownExpected {
+		in.ExpectedEvents = -1
+	}
+
+	result, err := c.ExecuteTestBatch(context.Background(), in)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Results) != len(tests) {
+		return nil, errors.Errorf("expected results for %d test case sets, got %d", len(tests), len(result.Results))
+	}
+
+	for _, line := range result.Logs {
+		s.log.Warningf("Logstash %s: %s", gjson.Get(line, "level").String(), logMessage(line))
+	}
+
+	results := make([]testResult, len(tests))
+	for i, t := range tests {
+		results[i].events, results[i].inputIDs, results[i].err = s.parseResults(result.Results[i].Results, t)
+
+		var drops []drop
+		for _, line := range result.Results[i].Logs {
+			if d, ok := parseDrop(line); ok {
+				s.log.Debugf("%s: %s", t.Name(), d)
+				drops = append(drops, d)
+			}
+		}
+		results[i].dropped = renderDrops(drops)
+	}
+	return results, nil
+}
+
+// parseResults post processes the results of a test case set and returns
+// them as events together with the index of the input line, each event
+// originates from.
+func (s Test) parseResults(lines []string, t testcase.TestCaseSet) ([]logstash.Event, []int, error) {
+	results, inputIDs, err := s.postProcessResults(lines, t)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var events []logstash.Event
+	for _, line := range results {
+		var event logstash.Event
+		err = json.Unmarshal([]byte(line), &event)
+		if err != nil {
+			return nil, nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, inputIDs, nil
 }
 
 func (s Test) createImplicitPipeline() (string, error) {
@@ -263,7 +811,10 @@ func (s Test) validateInputLines(lines []string) {
 	}
 }
 
-func (s Test) postProcessResults(results []string, t testcase.TestCaseSet) ([]string, error) {
+// postProcessResults sorts the results by the input line they originate
+// from and removes the LFV internal fields. The index of the input line is
+// returned for each result.
+func (s Test) postProcessResults(results []string, t testcase.TestCaseSet) ([]string, []int, error) {
 	var err error
 
 	sort.Slice(results, func(i, j int) bool {
@@ -275,18 +826,32 @@ func (s Test) postProcessResults(results []string, t testcase.TestCaseSet) ([]st
 		return idI < idJ
 	})
 
+	inputIDs := make([]int, len(results))
 	for i := 0; i < len(results); i++ {
+		inputIDs[i] = int(gjson.Get(results[i], `__lfv_metadata.__lfv_id`).Int())
+
+		if s.coverage != nil {
+			s.coverage.AddOutput(gjson.Get(results[i], `__lfv_metadata.__lfv_out_passed`).String(), 1)
+		}
+
 		if s.debug {
 			results[i], err = sjson.Set(results[i], `__lfv_id`, gjson.Get(results[i], `__lfv_metadata.__lfv_id`).String())
 			if err != nil {
-				return nil, err
+				return nil, nil, err
+			}
+		}
+
+		if s.trace {
+			results[i], err = sjson.Set(results[i], traceField, renderTrace(gjson.Get(results[i], `__lfv_metadata.__lfv_trace`)))
+			if err != nil {
+				return nil, nil, err
 			}
 		}
 
 		if t.ExportOutputs || s.debug {
 			results[i], err = sjson.Set(results[i], `__lfv_out_passed`, gjson.Get(results[i], `__lfv_metadata.__lfv_out_passed`).String())
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		}
 
@@ -304,14 +869,14 @@ func (s Test) postProcessResults(results []string, t testcase.TestCaseSet) ([]st
 				if len(md) > 0 {
 					results[i], err = sjson.Set(results[i], s.metadataKey, md)
 					if err != nil {
-						return nil, err
+						return nil, nil, err
 					}
 				}
 			}
 		}
 		results[i], err = sjson.Delete(results[i], "__lfv_metadata")
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		// No cleanup if debug is set
@@ -334,9 +899,9 @@ func (s Test) postProcessResults(results []string, t testcase.TestCaseSet) ([]st
 			results[i], err = sjson.Set(results[i], "tags", tags)
 		}
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
-	return results, nil
+	return results, inputIDs, nil
 }
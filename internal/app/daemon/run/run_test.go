@@ -0,0 +1,295 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"google.golang.org/grpc"
+
+	pb "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/api/grpc"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logstash"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/testcase"
+)
+
+// fakeControlClient echoes the first input line of each test as the
+// message of a single event.
+type fakeControlClient struct {
+	pb.ControlClient
+
+	mutex     sync.Mutex
+	sessions  map[string]bool
+	setups    int
+	failAfter int
+}
+
+func (f *fakeControlClient) SetupTest(ctx context.Context, in *pb.SetupTestRequest, opts ...grpc.CallOption) (*pb.SetupTestResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.setups++
+	id := fmt.Sprintf("session-%d", f.setups)
+	f.sessions[id] = true
+	return &pb.SetupTestResponse{SessionID: id}, nil
+}
+
+func (f *fakeControlClient) ExecuteTest(ctx context.Context, in *pb.ExecuteTestRequest, opts ...grpc.CallOption) (*pb.ExecuteTestResponse, error) {
+	// Finish the test case sets in a different order than they are started.
+	if in.InputLines[0] == "0" {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if in.InputLines[0] == fmt.Sprint(f.failAfter) {
+		return nil, fmt.Errorf("execute test %s failed", in.InputLines[0])
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if !f.sessions[in.SessionID] {
+		return nil, fmt.Errorf("unknown session %s", in.SessionID)
+	}
+	return &pb.ExecuteTestResponse{
+		Results: []string{fmt.Sprintf(`{"message": %q, "__lfv_metadata": {"__lfv_id": 0}}`, in.InputLines[0])},
+	}, nil
+}
+
+func (f *fakeControlClient) ExecuteTestStream(ctx context.Context, in *pb.ExecuteTestRequest, opts ...grpc.CallOption) (pb.Control_ExecuteTestStreamClient, error) {
+	result, err := f.ExecuteTest(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	stream := &fakeStreamClient{}
+	for _, event := range result.Results {
+		stream.responses = append(stream.responses,
+			&pb.ExecuteTestStreamResponse{Result: &pb.ExecuteTestStreamResponse_Event{Event: event}},
+			&pb.ExecuteTestStreamResponse{Result: &pb.ExecuteTestStreamResponse_Log{Log: `{"level":"WARN","logEvent":{"message":"warning"}}`}},
+		)
+	}
+	return stream, nil
+}
+
+// fakeStreamClient returns the responses one by one and io.EOF afterwards.
+type fakeStreamClient struct {
+	grpc.ClientStream
+
+	responses []*pb.ExecuteTestStreamResponse
+}
+
+func (f *fakeStreamClient) Recv() (*pb.ExecuteTestStreamResponse, error) {
+	if len(f.responses) == 0 {
+		return nil, io.EOF
+	}
+	response := f.responses[0]
+	f.responses = f.responses[1:]
+	return response, nil
+}
+
+func (f *fakeControlClient) ExecuteTestBatch(ctx context.Context, in *pb.ExecuteTestBatchRequest, opts ...grpc.CallOption) (*pb.ExecuteTestBatchResponse, error) {
+	out := &pb.ExecuteTestBatchResponse{}
+	for _, t := range in.TestCaseSets {
+		result, err := f.ExecuteTest(ctx, &pb.ExecuteTestRequest{
+			SessionID:  in.SessionID,
+			InputLines: t.InputLines,
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+		out.Results = append(out.Results, &pb.TestCaseSetResults{Results: result.Results})
+	}
+	return out, nil
+}
+
+func (f *fakeControlClient) TeardownTest(ctx context.Context, in *pb.TeardownTestRequest, opts ...grpc.CallOption) (*pb.TeardownTestResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if !f.sessions[in.SessionID] {
+		return nil, fmt.Errorf("unknown session %s", in.SessionID)
+	}
+	delete(f.sessions, in.SessionID)
+	if in.Stats {
+		return &pb.TeardownTestResponse{Stats: &pb.SessionStats{TestExecutions: 1}}, nil
+	}
+	return &pb.TeardownTestResponse{}, nil
+}
+
+func TestExecuteParallel(t *testing.T) {
+	cases := []struct {
+		name      string
+		parallel  int
+		tests     int
+		failAfter int
+		batch     bool
+
+		wantSetups  int
+		wantHandled int
+		wantErr     bool
+	}{
+		{
+			name:      "more tests than sessions",
+			parallel:  3,
+			tests:     10,
+			failAfter: -1,
+
+			wantSetups:  3,
+			wantHandled: 10,
+		},
+		{
+			name:      "more sessions than tests",
+			parallel:  4,
+			tests:     2,
+			failAfter: -1,
+
+			wantSetups:  2,
+			wantHandled: 2,
+		},
+		{
+			name:      "error",
+			parallel:  2,
+			tests:     10,
+			failAfter: 3,
+
+			wantSetups:  2,
+			wantHandled: 3,
+			wantErr:     true,
+		},
+		{
+			name:      "batch",
+			parallel:  3,
+			tests:     10,
+			failAfter: -1,
+			batch:     true,
+
+			wantSetups:  3,
+			wantHandled: 10,
+		},
+		{
+			name:      "batch error",
+			parallel:  2,
+			tests:     10,
+			failAfter: 7,
+			batch:     true,
+
+			wantSetups:  2,
+			wantHandled: 5, // the whole second batch fails
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			tests := make([]testcase.TestCaseSet, test.tests)
+			for i := range tests {
+				tests[i] = testcase.TestCaseSet{
+					InputLines: []string{fmt.Sprint(i)},
+				}
+			}
+
+			c := &fakeControlClient{
+				sessions:  map[string]bool{},
+				failAfter: test.failAfter,
+			}
+			s := Test{
+				parallel: test.parallel,
+				batch:    test.batch,
+				log:      logging.NoopLogger,
+			}
+
+			handled := []string{}
+			err := s.executeParallel(c, nil, tests, false, func(t testcase.TestCaseSet, events []logstash.Event, inputIDs []int, _ []string) error {
+				is.Equal(len(events), 1)
+				is.Equal(inputIDs, []int{0})
+				handled = append(handled, events[0]["message"].(string))
+				return nil
+			})
+			is.Equal(err != nil, test.wantErr) // error
+
+			want := []string{}
+			for i := 0; i < test.wantHandled; i++ {
+				want = append(want, fmt.Sprint(i))
+			}
+			is.Equal(handled, want)             // results in the order of the test case sets
+			is.Equal(c.setups, test.wantSetups) // number of sessions
+			is.Equal(len(c.sessions), 0)        // all sessions torn down
+		})
+	}
+}
+
+func TestPrintStatsParallel(t *testing.T) {
+	cases := []struct {
+		name         string
+		outputFormat string
+	}{
+		{
+			name:         "text",
+			outputFormat: "text",
+		},
+		{
+			name:         "json",
+			outputFormat: "json",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			tests := make([]testcase.TestCaseSet, 6)
+			for i := range tests {
+				tests[i] = testcase.TestCaseSet{
+					InputLines: []string{fmt.Sprint(i)},
+				}
+			}
+
+			c := &fakeControlClient{
+				sessions:  map[string]bool{},
+				failAfter: -1,
+			}
+			s := Test{
+				parallel:       3,
+				stats:          true,
+				outputFormat:   test.outputFormat,
+				collectedStats: &statsCollector{},
+				log:            logging.NoopLogger,
+			}
+
+			err := s.executeParallel(c, nil, tests, false, func(testcase.TestCaseSet, []logstash.Event, []int, []string) error {
+				return nil
+			})
+			is.NoErr(err)
+
+			var out bytes.Buffer
+			err = s.printStats(&out)
+			is.NoErr(err)
+
+			switch test.outputFormat {
+			case "json":
+				lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+				is.Equal(len(lines), 3) // one record per session
+				for _, line := range lines {
+					var record map[string]interface{}
+					is.NoErr(json.Unmarshal([]byte(line), &record))
+					is.Equal(record["type"], "stats")
+				}
+			default:
+				is.Equal(strings.Count(out.String(), "Statistics of session"), 3) // one table per session
+			}
+
+			out.Reset()
+			err = s.printStats(&out)
+			is.NoErr(err)
+			is.Equal(out.String(), "") // statistics are printed only once
+		})
+	}
+}
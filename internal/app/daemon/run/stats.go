@@ -0,0 +1,163 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/api/grpc"
+	lfvobserver "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/observer"
+)
+
+// statsCollector collects the statistics of the test sessions. The test
+// sessions executed in parallel are torn down concurrently, therefore the
+// statistics are printed at once after the test run.
+type statsCollector struct {
+	mutex    sync.Mutex
+	sessions []sessionStats
+}
+
+type sessionStats struct {
+	id    string
+	stats *pb.SessionStats
+}
+
+func (c *statsCollector) add(sessionID string, stats *pb.SessionStats) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.sessions = append(c.sessions, sessionStats{id: sessionID, stats: stats})
+}
+
+// drain returns the statistics collected so far and removes them from the
+// collector.
+func (c *statsCollector) drain() []sessionStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	sessions := c.sessions
+	c.sessions = nil
+	return sessions
+}
+
+// printStats prints the statistics of the test sessions torn down so far
+// in the output format of the test results, that is either as text or as
+// a JSON object per test session.
+func (s Test) printStats(out io.Writer) error {
+	if s.collectedStats == nil {
+		return nil
+	}
+	for _, session := range s.collectedStats.drain() {
+		var err error
+		if s.outputFormat == lfvobserver.OutputFormatJSON {
+			err = writeStatsJSON(out, session.id, session.stats)
+		} else {
+			err = writeStats(out, session.id, session.stats)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeStats prints the statistics of a test session as text.
+func writeStats(out io.Writer, sessionID string, stats *pb.SessionStats) error {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Statistics of session %s:\n", sessionID)
+	fmt.Fprintf(w, "  Test executions:\t%d\n", stats.TestExecutions)
+	fmt.Fprintf(w, "  Events in:\t%d\n", stats.EventsIn)
+	fmt.Fprintf(w, "  Events out:\t%d\n", stats.EventsOut)
+
+	if len(stats.TestExecutionStats) > 0 {
+		fmt.Fprintf(w, "\n  EXECUTION\tEVENTS IN\tEVENTS OUT\tRELOAD\tPROCESSING\n")
+		for i, execution := range stats.TestExecutionStats {
+			fmt.Fprintf(w, "  %d\t%d\t%d\t%s\t%s\n", i+1, execution.EventsIn, execution.EventsOut, millis(execution.ReloadMillis), millis(execution.ProcessingMillis))
+		}
+	}
+
+	if len(stats.PluginStats) > 0 {
+		fmt.Fprintf(w, "\n  PIPELINE\tPLUGIN ID\tPLUGIN\tEVENTS IN\tEVENTS OUT\tDURATION\n")
+		for _, plugin := range stats.PluginStats {
+			fmt.Fprintf(w, "  %s\t%s\t%s %s\t%d\t%d\t%s\n", plugin.PipelineID, plugin.Id, plugin.Kind, plugin.Name, plugin.EventsIn, plugin.EventsOut, millis(plugin.DurationMillis))
+		}
+	}
+
+	err := w.Flush()
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(buf.Bytes())
+	return err
+}
+
+type jsonStats struct {
+	Type           string               `json:"type"`
+	Session        string               `json:"session"`
+	TestExecutions int32                `json:"test_executions"`
+	EventsIn       int64                `json:"events_in"`
+	EventsOut      int64                `json:"events_out"`
+	Executions     []jsonExecutionStats `json:"executions"`
+	Plugins        []jsonPluginStats    `json:"plugins"`
+}
+
+type jsonExecutionStats struct {
+	EventsIn         int64 `json:"events_in"`
+	EventsOut        int64 `json:"events_out"`
+	ReloadMillis     int64 `json:"reload_ms"`
+	ProcessingMillis int64 `json:"processing_ms"`
+}
+
+type jsonPluginStats struct {
+	Pipeline       string `json:"pipeline"`
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Kind           string `json:"kind"`
+	EventsIn       int64  `json:"events_in"`
+	EventsOut      int64  `json:"events_out"`
+	DurationMillis int64  `json:"duration_ms"`
+}
+
+// writeStatsJSON prints the statistics of a test session as a single JSON
+// object of type stats, which fits into the output of --output-format json.
+func writeStatsJSON(out io.Writer, sessionID string, stats *pb.SessionStats) error {
+	record := jsonStats{
+		Type:           "stats",
+		Session:        sessionID,
+		TestExecutions: stats.TestExecutions,
+		EventsIn:       stats.EventsIn,
+		EventsOut:      stats.EventsOut,
+		Executions:     make([]jsonExecutionStats, 0, len(stats.TestExecutionStats)),
+		Plugins:        make([]jsonPluginStats, 0, len(stats.PluginStats)),
+	}
+	for _, execution := range stats.TestExecutionStats {
+		record.Executions = append(record.Executions, jsonExecutionStats{
+			EventsIn:         execution.EventsIn,
+			EventsOut:        execution.EventsOut,
+			ReloadMillis:     execution.ReloadMillis,
+			ProcessingMillis: execution.ProcessingMillis,
+		})
+	}
+	for _, plugin := range stats.PluginStats {
+		record.Plugins = append(record.Plugins, jsonPluginStats{
+			Pipeline:       plugin.PipelineID,
+			ID:             plugin.Id,
+			Name:           plugin.Name,
+			Kind:           plugin.Kind,
+			EventsIn:       plugin.EventsIn,
+			EventsOut:      plugin.EventsOut,
+			DurationMillis: plugin.DurationMillis,
+		})
+	}
+	return json.NewEncoder(out).Encode(record)
+}
+
+func millis(ms int64) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
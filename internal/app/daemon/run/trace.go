@@ -0,0 +1,71 @@
+package run
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logstash"
+)
+
+// traceField is the field of the events, which temporarily holds the
+// rendered filter trace until the events are compared.
+const traceField = "__lfv_trace"
+
+// renderTrace renders the changes recorded by the trace instrumentation
+// as a numbered list of steps, e.g.
+//
+//  1. grok (id: parse): set [verb] = "GET"; removed [message]
+func renderTrace(trace gjson.Result) string {
+	var sb strings.Builder
+	step := 0
+	trace.ForEach(func(_, change gjson.Result) bool {
+		step++
+		var parts []string
+
+		var set []string
+		change.Get("set").ForEach(func(field, value gjson.Result) bool {
+			set = append(set, fmt.Sprintf("%s = %s", field.String(), value.Raw))
+			return true
+		})
+		if len(set) > 0 {
+			parts = append(parts, "set "+strings.Join(set, ", "))
+		}
+
+		var removed []string
+		change.Get("removed").ForEach(func(_, field gjson.Result) bool {
+			removed = append(removed, field.String())
+			return true
+		})
+		if len(removed) > 0 {
+			parts = append(parts, "removed "+strings.Join(removed, ", "))
+		}
+
+		fmt.Fprintf(&sb, "  %d. %s (id: %s): %s\n", step, change.Get("plugin").String(), change.Get("id").String(), strings.Join(parts, "; "))
+		return true
+	})
+
+	if step == 0 {
+		return "  no filter changed the event\n"
+	}
+	return sb.String()
+}
+
+// extractTraces removes the rendered filter traces from the events and
+// returns them. If tracing is disabled, nil is returned.
+func extractTraces(events []logstash.Event) []string {
+	var traces []string
+	for i, event := range events {
+		trace, ok := event[traceField].(string)
+		if !ok {
+			continue
+		}
+		delete(event, traceField)
+		if traces == nil {
+			traces = make([]string, len(events))
+		}
+		traces[i] = trace
+	}
+	return traces
+}
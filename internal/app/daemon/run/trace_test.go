@@ -0,0 +1,58 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/tidwall/gjson"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logstash"
+)
+
+func TestRenderTrace(t *testing.T) {
+	cases := []struct {
+		name  string
+		trace string
+
+		want string
+	}{
+		{
+			name: "set and removed fields",
+			trace: `[
+  {"id": "parse", "plugin": "grok", "set": {"[verb]": "GET", "[user][name]": "john"}, "removed": []},
+  {"id": "cleanup", "plugin": "mutate", "set": {"[tags]": ["get"]}, "removed": ["[message]"]}
+]`,
+
+			want: `  1. grok (id: parse): set [verb] = "GET", [user][name] = "john"
+  2. mutate (id: cleanup): set [tags] = ["get"]; removed [message]
+`,
+		},
+		{
+			name:  "no changes",
+			trace: ``,
+
+			want: "  no filter changed the event\n",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			is.Equal(renderTrace(gjson.Parse(test.trace)), test.want)
+		})
+	}
+}
+
+func TestExtractTraces(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(extractTraces([]logstash.Event{{"message": "a"}}), nil) // tracing disabled
+
+	events := []logstash.Event{
+		{"message": "a", traceField: "  1. step\n"},
+		{"message": "b"},
+	}
+	is.Equal(extractTraces(events), []string{"  1. step\n", ""})
+	is.Equal(events[0], logstash.Event{"message": "a"}) // trace removed from the event
+}
@@ -0,0 +1,358 @@
+package run
+
+import (
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v2"
+	"github.com/fsnotify/fsnotify"
+	"github.com/imkira/go-observer"
+
+	pb "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/api/grpc"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pipeline"
+	lfvobserver "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/observer"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/testcase"
+)
+
+// debounceDelay is the time to wait for further changes, before the test
+// cases are executed again. Editors often write a file in multiple steps
+// and a change often affects multiple files.
+const debounceDelay = 300 * time.Millisecond
+
+// watchTargets contains the files, which are watched for changes.
+type watchTargets struct {
+	// configPatterns contains the absolute glob patterns of the files,
+	// which require the test session to be set up again on change
+	// (pipelines.yml, Logstash config files and plugin mock file).
+	configPatterns []string
+
+	// testcasePath is the absolute path of the test case file or
+	// directory.
+	testcasePath string
+
+	// dirs contains the directories to be watched.
+	dirs []string
+}
+
+// Watch executes the test cases like Run and afterwards watches the
+// Logstash config, the plugin mock file and the test case files for
+// changes. If the Logstash config or the plugin mock file changes, the test
+// session is set up again and all the test cases are executed. If only
+// test case files change, only these test case files are executed again.
+// Watch returns, when the process receives an interrupt signal.
+func (s Test) Watch() error {
+	liveObserver, observers, err := s.startObservers()
+	if err != nil {
+		return err
+	}
+
+	conn, err := s.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	c := pb.NewControlClient(conn)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	var (
+		targets   watchTargets
+		sessionID string
+		inputs    map[string]int
+		setup     = true
+		changed   map[string]bool
+	)
+
+	defer func() {
+		if sessionID == "" {
+			return
+		}
+		if err := s.teardownTest(c, sessionID); err != nil {
+			s.log.Errorf("failed to teardown connection: %v", err)
+		}
+		if err := s.printStats(os.Stdout); err != nil {
+			s.log.Errorf("failed to print the statistics: %v", err)
+		}
+	}()
+
+	for {
+		if setup {
+			targets, err = s.watchTargets()
+			if err != nil {
+				return err
+			}
+			for _, dir := range targets.dirs {
+				if err := watcher.Add(dir); err != nil {
+					s.log.Warningf("Failed to watch %s: %s", dir, err)
+				}
+			}
+
+			if sessionID != "" {
+				// The daemon might already have destroyed the test session
+				// after a failed test execution.
+				if err := s.teardownTest(c, sessionID); err != nil {
+					s.log.Warningf("Failed to teardown the test session: %s", err)
+				}
+				sessionID = ""
+				if err := s.printStats(os.Stdout); err != nil {
+					return err
+				}
+			}
+
+			var b []byte
+			b, inputs, err = s.zipPipeline()
+			if err == nil {
+				sessionID, err = s.setupTest(c, b)
+				if err != nil {
+					s.dumpSetupFailure(b)
+				}
+			}
+			if err != nil {
+				s.log.Errorf("Failed to set up the test session: %s", err)
+			} else {
+				setup = false
+				changed = nil
+			}
+		}
+
+		if sessionID != "" {
+			err = s.executeChanged(c, sessionID, inputs, changed, liveObserver, observers)
+			if err != nil {
+				s.log.Errorf("Failed to execute the test cases: %s", err)
+				// The test session might be broken (e.g. after a crash of
+				// Logstash), so it is set up again with the next change.
+				setup = true
+			}
+		}
+
+		s.log.Infof("Watching for changes, press Ctrl-C to stop")
+
+		var ok bool
+		changed, ok = s.waitForChanges(watcher, sigs, targets)
+		if !ok {
+			return nil
+		}
+		for file := range changed {
+			if targets.isConfig(file) {
+				setup = true
+				break
+			}
+		}
+	}
+}
+
+// executeChanged executes the test case sets, whose test case file is
+// contained in changed, or all the test case sets, if changed is nil.
+func (s Test) executeChanged(c pb.ControlClient, sessionID string, inputs map[string]int, changed map[string]bool, liveObserver observer.Property, observers []lfvobserver.Interface) (err error) {
+	tests, err := s.discoverTests()
+	if err != nil {
+		return err
+	}
+
+	if changed != nil {
+		selected := make([]testcase.TestCaseSet, 0, len(tests))
+		for _, t := range tests {
+			if changed[t.File] {
+				selected = append(selected, t)
+			}
+		}
+		tests = selected
+	}
+	if len(tests) == 0 {
+		return nil
+	}
+
+	err = checkInputPlugins(tests, inputs)
+	if err != nil {
+		return err
+	}
+
+	liveObserver.Update(lfvobserver.TestExecutionStart{})
+	defer func() {
+		liveObserver.Update(lfvobserver.TestExecutionEnd{})
+		for _, obs := range observers {
+			if finalizeErr := obs.Finalize(); finalizeErr != nil && err == nil {
+				err = finalizeErr
+			}
+		}
+	}()
+
+	testsPassed := true
+	return s.executeTests(c, sessionID, tests, s.update, s.compareResults(liveObserver, &testsPassed))
+}
+
+// waitForChanges blocks until the watched files change and returns the
+// absolute paths of the changed files. False is returned, if the process
+// received a signal.
+func (s Test) waitForChanges(watcher *fsnotify.Watcher, sigs <-chan os.Signal, targets watchTargets) (map[string]bool, bool) {
+	changed := map[string]bool{}
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-sigs:
+			return nil, false
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil, false
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			if !targets.isConfig(event.Name) && !targets.isTestcase(event.Name) {
+				continue
+			}
+			// Watch new subdirectories of the test case directory.
+			if event.Op&fsnotify.Create == fsnotify.Create && targets.isTestcase(event.Name) {
+				if fi, err := os.Stat(event.Name); err == nil && fi.IsDir() {
+					if err := watcher.Add(event.Name); err != nil {
+						s.log.Warningf("Failed to watch %s: %s", event.Name, err)
+					}
+				}
+			}
+			s.log.Debugf("File changed: %s", event)
+			changed[event.Name] = true
+			debounce = time.After(debounceDelay)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil, false
+			}
+			s.log.Warningf("Error while watching for changes: %s", err)
+		case <-debounce:
+			return changed, true
+		}
+	}
+}
+
+// watchTargets returns the files and directories to be watched for
+// changes.
+func (s Test) watchTargets() (watchTargets, error) {
+	var (
+		targets watchTargets
+		err     error
+	)
+
+	if s.logstashConfig != "" {
+		config, err := filepath.Abs(s.logstashConfig)
+		if err != nil {
+			return watchTargets{}, err
+		}
+		if fi, err := os.Stat(config); err == nil && fi.IsDir() {
+			config = filepath.Join(config, "*")
+		}
+		targets.configPatterns = append(targets.configPatterns, config)
+	} else {
+		pipelineFile, err := filepath.Abs(s.pipeline)
+		if err != nil {
+			return watchTargets{}, err
+		}
+		targets.configPatterns = append(targets.configPatterns, pipelineFile)
+
+		a, err := pipeline.New(s.pipeline, s.pipelineBase)
+		if err != nil {
+			return watchTargets{}, err
+		}
+		for _, pattern := range a.ConfigPatterns() {
+			pattern, err = filepath.Abs(pattern)
+			if err != nil {
+				return watchTargets{}, err
+			}
+			targets.configPatterns = append(targets.configPatterns, pattern)
+		}
+	}
+
+	if s.pluginMock != "" {
+		pluginMock, err := filepath.Abs(s.pluginMock)
+		if err != nil {
+			return watchTargets{}, err
+		}
+		targets.configPatterns = append(targets.configPatterns, pluginMock)
+	}
+
+	for _, pattern := range targets.configPatterns {
+		base := globBase(pattern)
+		if base == pattern {
+			targets.dirs = append(targets.dirs, filepath.Dir(pattern))
+			continue
+		}
+		dirs, err := subdirectories(base)
+		if err != nil {
+			return watchTargets{}, err
+		}
+		targets.dirs = append(targets.dirs, dirs...)
+	}
+
+	targets.testcasePath, err = filepath.Abs(s.testcasePath)
+	if err != nil {
+		return watchTargets{}, err
+	}
+	if fi, err := os.Stat(targets.testcasePath); err == nil && !fi.IsDir() {
+		targets.dirs = append(targets.dirs, filepath.Dir(targets.testcasePath))
+	} else {
+		dirs, err := subdirectories(targets.testcasePath)
+		if err != nil {
+			return watchTargets{}, err
+		}
+		targets.dirs = append(targets.dirs, dirs...)
+	}
+
+	return targets, nil
+}
+
+// isConfig returns true, if a change of the file requires the test session
+// to be set up again.
+func (t watchTargets) isConfig(file string) bool {
+	for _, pattern := range t.configPatterns {
+		if match, err := doublestar.PathMatch(pattern, file); err == nil && match {
+			return true
+		}
+	}
+	return false
+}
+
+// isTestcase returns true, if the file is the test case file or is located
+// in the test case directory.
+func (t watchTargets) isTestcase(file string) bool {
+	return file == t.testcasePath || strings.HasPrefix(file, t.testcasePath+string(filepath.Separator))
+}
+
+// globBase returns the leading part of the pattern, which does not contain
+// any glob meta characters.
+func globBase(pattern string) string {
+	i := strings.IndexAny(pattern, "*?[{\\")
+	if i < 0 {
+		return pattern
+	}
+	return filepath.Dir(pattern[:i+1])
+}
+
+// subdirectories returns the directory and all its subdirectories. A
+// missing directory results in an empty list.
+func subdirectories(dir string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	return dirs, err
+}
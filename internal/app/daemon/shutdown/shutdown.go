@@ -2,40 +2,32 @@ package shutdown
 
 import (
 	"context"
-	"net"
-	"time"
-
-	"google.golang.org/grpc"
+	"crypto/tls"
 
 	pb "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/api/grpc"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/transport"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
 )
 
 type Shutdown struct {
-	socket string
+	address   string
+	tlsConfig *tls.Config
 
 	log logging.Logger
 }
 
-func New(socket string, log logging.Logger) Shutdown {
+func New(address string, tlsConfig *tls.Config, log logging.Logger) Shutdown {
 	return Shutdown{
-		socket: socket,
-		log:    log,
+		address:   address,
+		tlsConfig: tlsConfig,
+		log:       log,
 	}
 }
 
 func (s Shutdown) Run() error {
-	s.log.Debug("Shutdown on socket ", s.socket)
-
-	conn, err := grpc.Dial(
-		s.socket,
-		grpc.WithInsecure(), //nolint:staticcheck
-		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
-			if d, ok := ctx.Deadline(); ok {
-				return net.DialTimeout("unix", addr, time.Until(d))
-			}
-			return net.Dial("unix", addr)
-		}))
+	s.log.Debug("Shutdown on ", s.address)
+
+	conn, err := transport.Dial(s.address, s.tlsConfig)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,88 @@
+package status
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/api/grpc"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/transport"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
+)
+
+type Status struct {
+	address   string
+	tlsConfig *tls.Config
+
+	log logging.Logger
+}
+
+func New(address string, tlsConfig *tls.Config, log logging.Logger) Status {
+	return Status{
+		address:   address,
+		tlsConfig: tlsConfig,
+		log:       log,
+	}
+}
+
+// Run requests the status from the daemon and prints it to stdout.
+func (s Status) Run() error {
+	s.log.Debug("Status on ", s.address)
+
+	conn, err := transport.Dial(s.address, s.tlsConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	c := pb.NewControlClient(conn)
+
+	status, err := c.Status(context.Background(), &pb.StatusRequest{})
+	if err != nil {
+		return err
+	}
+
+	return write(os.Stdout, status)
+}
+
+// write prints the status in a human readable form.
+func write(out io.Writer, status *pb.StatusResponse) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Logstash:\t%s (%s)\n", status.LogstashVersion, status.LogstashPath)
+	fmt.Fprintf(w, "Uptime:\t%s\n", seconds(status.UptimeSeconds))
+	fmt.Fprintf(w, "Pool size:\t%d\n", status.PoolSize)
+
+	fmt.Fprintf(w, "\nLogstash instances:\n")
+	if len(status.LogstashInstances) == 0 {
+		fmt.Fprintf(w, "  none\n")
+	} else {
+		fmt.Fprintf(w, "  ID\tSTATE\tASSIGNED\n")
+		for _, instance := range status.LogstashInstances {
+			assigned := "no"
+			if instance.Assigned {
+				assigned = "yes"
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", instance.Id, instance.State, assigned)
+		}
+	}
+
+	fmt.Fprintf(w, "\nSessions:\n")
+	if len(status.Sessions) == 0 {
+		fmt.Fprintf(w, "  none\n")
+	} else {
+		fmt.Fprintf(w, "  ID\tAGE\tTEST EXECUTIONS\tLOGSTASH INSTANCE\n")
+		for _, session := range status.Sessions {
+			fmt.Fprintf(w, "  %s\t%s\t%d\t%s\n", session.Id, seconds(session.AgeSeconds), session.TestExecutions, session.LogstashInstanceID)
+		}
+	}
+
+	return w.Flush()
+}
+
+func seconds(s int64) time.Duration {
+	return time.Duration(s) * time.Second
+}
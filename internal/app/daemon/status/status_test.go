@@ -0,0 +1,80 @@
+package status
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/matryer/is"
+
+	pb "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/api/grpc"
+)
+
+func TestWrite(t *testing.T) {
+	cases := []struct {
+		name   string
+		status *pb.StatusResponse
+
+		want string
+	}{
+		{
+			name: "instances and sessions",
+			status: &pb.StatusResponse{
+				LogstashVersion: "8.11.0",
+				LogstashPath:    "/usr/share/logstash/bin/logstash",
+				PoolSize:        2,
+				UptimeSeconds:   3723,
+				LogstashInstances: []*pb.LogstashInstanceStatus{
+					{Id: "instance-1", State: "running_test", Assigned: true},
+					{Id: "instance-2", State: "ready", Assigned: false},
+				},
+				Sessions: []*pb.SessionStatus{
+					{Id: "session-1", AgeSeconds: 75, TestExecutions: 12, LogstashInstanceID: "instance-1"},
+				},
+			},
+
+			want: `Logstash:   8.11.0 (/usr/share/logstash/bin/logstash)
+Uptime:     1h2m3s
+Pool size:  2
+
+Logstash instances:
+  ID          STATE         ASSIGNED
+  instance-1  running_test  yes
+  instance-2  ready         no
+
+Sessions:
+  ID         AGE    TEST EXECUTIONS  LOGSTASH INSTANCE
+  session-1  1m15s  12               instance-1
+`,
+		},
+		{
+			name: "idle",
+			status: &pb.StatusResponse{
+				LogstashVersion: "7.17.0",
+				LogstashPath:    "logstash",
+				PoolSize:        1,
+			},
+
+			want: `Logstash:   7.17.0 (logstash)
+Uptime:     0s
+Pool size:  1
+
+Logstash instances:
+  none
+
+Sessions:
+  none
+`,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			out := &bytes.Buffer{}
+			err := write(out, test.status)
+			is.NoErr(err)
+			is.Equal(out.String(), test.want)
+		})
+	}
+}
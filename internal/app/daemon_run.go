@@ -1,12 +1,14 @@
 package app
 
 import (
+	"github.com/mattn/go-shellwords"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/app/daemon/run"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/testcase"
 )
 
 func makeDaemonRunCmd() *cobra.Command {
@@ -32,12 +34,45 @@ func makeDaemonRunCmd() *cobra.Command {
 	_ = viper.BindPFlag("metadata-key", cmd.Flags().Lookup("metadata-key"))
 	cmd.Flags().Bool("add-missing-id", false, "add implicit id for the plugins in the Logstash config if they are missing")
 	_ = viper.BindPFlag("add-missing-id", cmd.Flags().Lookup("add-missing-id"))
+	cmd.Flags().Bool("update", false, "rewrite the expected events in the test case files of the failing tests with the actual events")
+	_ = viper.BindPFlag("daemon-update", cmd.Flags().Lookup("update"))
+	cmd.Flags().String("diff-command", "", "command to run to compare two events, the command will receive the two files to compare as arguments; if empty, the events are compared with the builtin comparator")
+	_ = viper.BindPFlag("daemon-diff-command", cmd.Flags().Lookup("diff-command"))
+	cmd.Flags().StringSlice("report", nil, "write a report of the test results in the format <type>:<path> (e.g. junit:report.xml), supported types: junit; may be given multiple times")
+	_ = viper.BindPFlag("daemon-reports", cmd.Flags().Lookup("report"))
+	cmd.Flags().String("output-format", "text", "format of the test results printed to stdout, one of: text, json (one JSON object per line)")
+	_ = viper.BindPFlag("daemon-output-format", cmd.Flags().Lookup("output-format"))
+	cmd.Flags().StringSlice("testcase-include", testcase.DefaultIncludePatterns, "only discover the test case files matching at least one of the patterns (e.g. **/*.json), relative to the test case directory; may be given multiple times")
+	_ = viper.BindPFlag("daemon-testcase-include", cmd.Flags().Lookup("testcase-include"))
+	cmd.Flags().StringSlice("testcase-exclude", nil, "skip the test case files and directories matching at least one of the patterns, relative to the test case directory; may be given multiple times")
+	_ = viper.BindPFlag("daemon-testcase-exclude", cmd.Flags().Lookup("testcase-exclude"))
+	cmd.Flags().Int("parallel", 1, "number of test sessions to execute the test case files in parallel; should not exceed the --pool-size of the daemon")
+	_ = viper.BindPFlag("daemon-parallel", cmd.Flags().Lookup("parallel"))
+	cmd.Flags().Bool("batch", false, "execute all the test case files with a single reload of the Logstash config; the events of different test case files may be interleaved in the pipelines")
+	_ = viper.BindPFlag("daemon-batch", cmd.Flags().Lookup("batch"))
+	cmd.Flags().Bool("stats", false, "print the statistics of each test session (test executions, events, reload and processing latency, plugin event counts and durations)")
+	_ = viper.BindPFlag("daemon-stats", cmd.Flags().Lookup("stats"))
+	cmd.Flags().StringSlice("coverage", nil, "write a report of the plugins and conditional branches of the Logstash config exercised by the test cases in the format <format>[:<path>] (e.g. html:coverage.html), supported formats: text (stdout, if no path is given), json, html; may be given multiple times")
+	_ = viper.BindPFlag("daemon-coverage", cmd.Flags().Lookup("coverage"))
+	cmd.Flags().Bool("trace", false, "record the changes of each filter to the events and show them step by step for the failed test cases")
+	_ = viper.BindPFlag("daemon-trace", cmd.Flags().Lookup("trace"))
+	cmd.Flags().Bool("report-drops", false, "report the events removed by drop filters together with the conditional branches leading to them for the failed test cases")
+	_ = viper.BindPFlag("daemon-report-drops", cmd.Flags().Lookup("report-drops"))
+	cmd.Flags().String("dump-config", "", "write the Logstash configuration loaded by the daemon for each test session (rewritten configs, generated pipelines and pipelines.yml) to a sub directory of this directory")
+	_ = viper.BindPFlag("daemon-dump-config", cmd.Flags().Lookup("dump-config"))
+	cmd.Flags().Bool("watch", false, "keep running and execute the test cases again, when the Logstash config, the plugin mock file or the test case files change")
+	_ = viper.BindPFlag("daemon-watch", cmd.Flags().Lookup("watch"))
+	cmd.Flags().String("run", "", "only run the test cases, whose description or test case file name matches the regular expression")
+	_ = viper.BindPFlag("daemon-run", cmd.Flags().Lookup("run"))
+	cmd.Flags().StringSlice("tags", nil, "only run the test cases with at least one of the given tags; may be given multiple times")
+	_ = viper.BindPFlag("daemon-tags", cmd.Flags().Lookup("tags"))
+	cmd.Flags().StringSlice("skip-tags", nil, "skip the test cases with at least one of the given tags; may be given multiple times")
+	_ = viper.BindPFlag("daemon-skip-tags", cmd.Flags().Lookup("skip-tags"))
 
 	return cmd
 }
 
 func runDaemonRun(_ *cobra.Command, args []string) error {
-	socket := viper.GetString("socket")
 	log := viper.Get("logger").(logging.Logger)
 	pipeline := viper.GetString("pipeline")
 	pipelineBase := viper.GetString("pipeline-base")
@@ -47,15 +82,79 @@ func runDaemonRun(_ *cobra.Command, args []string) error {
 	debug := viper.GetBool("debug")
 	metadataKey := viper.GetString("metadata-key")
 	addMissingID := viper.GetBool("add-missing-id")
+	update := viper.GetBool("daemon-update")
+	reports := viper.GetStringSlice("reports")
+	if len(viper.GetStringSlice("daemon-reports")) > 0 {
+		reports = viper.GetStringSlice("daemon-reports")
+	}
+	outputFormat := viper.GetString("daemon-output-format")
+	include := viper.GetStringSlice("daemon-testcase-include")
+	exclude := viper.GetStringSlice("daemon-testcase-exclude")
+	// The default of the standalone flag (diff -u) does not apply to daemon
+	// run, only a diff command set in the config file.
+	diffCommand := viper.GetString("daemon-diff-command")
+	if diffCommand == "" && viper.InConfig("diff-command") {
+		diffCommand = viper.GetString("diff-command")
+	}
 
 	if pipeline != "" && logstashConfig != "" {
 		return errors.New("--pipeline and --logstash-config flags are mutual exclusive")
 	}
 
-	t, err := run.New(socket, log, pipeline, pipelineBase, logstashConfig, testcaseDir, pluginMock, metadataKey, debug, addMissingID)
+	diffCmd, err := shellwords.NewParser().Parse(diffCommand)
+	if err != nil {
+		return errors.Errorf("error parsing diff command %q: %s", diffCommand, err)
+	}
+
+	filter, err := testcase.NewFilter(viper.GetString("daemon-run"), viper.GetStringSlice("daemon-tags"), viper.GetStringSlice("daemon-skip-tags"))
+	if err != nil {
+		return errors.Errorf("--run: %s", err)
+	}
+
+	tlsConfig, err := tlsFiles(viper.GetString).ClientConfig()
 	if err != nil {
 		return err
 	}
 
+	t, err := run.New(run.Options{
+		Address:         daemonAddress(viper.GetString),
+		TLSConfig:       tlsConfig,
+		Pipeline:        pipeline,
+		PipelineBase:    pipelineBase,
+		LogstashConfig:  logstashConfig,
+		TestcasePath:    testcaseDir,
+		PluginMock:      pluginMock,
+		MetadataKey:     metadataKey,
+		Debug:           debug,
+		AddMissingID:    addMissingID,
+		Update:          update,
+		DiffCommand:     diffCmd,
+		Reports:         reports,
+		OutputFormat:    outputFormat,
+		Include:         include,
+		Exclude:         exclude,
+		Filter:          filter,
+		Parallel:        viper.GetInt("daemon-parallel"),
+		Batch:           viper.GetBool("daemon-batch"),
+		Stats:           viper.GetBool("daemon-stats"),
+		CoverageReports: viper.GetStringSlice("daemon-coverage"),
+		Trace:           viper.GetBool("daemon-trace"),
+		ReportDrops:     viper.GetBool("daemon-report-drops"),
+		DumpConfig:      viper.GetString("daemon-dump-config"),
+	}, log)
+	if err != nil {
+		return err
+	}
+
+	if viper.GetBool("daemon-watch") {
+		if len(viper.GetStringSlice("daemon-coverage")) > 0 {
+			return errors.New("--coverage can not be used together with --watch")
+		}
+		if viper.GetInt("daemon-parallel") > 1 {
+			return errors.New("--parallel can not be used together with --watch")
+		}
+		return t.Watch()
+	}
+
 	return t.Run()
 }
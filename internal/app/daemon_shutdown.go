@@ -19,7 +19,12 @@ func makeDaemonShutdownCmd() *cobra.Command {
 }
 
 func runDaemonShutdown(_ *cobra.Command, _ []string) error {
-	s := shutdown.New(viper.GetString("socket"), viper.Get("logger").(logging.Logger))
+	tlsConfig, err := tlsFiles(viper.GetString).ClientConfig()
+	if err != nil {
+		return err
+	}
+
+	s := shutdown.New(daemonAddress(viper.GetString), tlsConfig, viper.Get("logger").(logging.Logger))
 
 	return s.Run()
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/app/daemon"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/transport"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
 )
 
@@ -36,6 +37,15 @@ func makeDaemonStartCmd() *cobra.Command {
 	cmd.Flags().Duration("wait-for-late-arrivals-timeout", 50*time.Millisecond, "duration to wait for late arriving events from Logstash (e.g. to test Logstash filters with a timeout like aggregation filter)")
 	_ = viper.BindPFlag("wait-for-late-arrivals-timeout", cmd.Flags().Lookup("wait-for-late-arrivals-timeout"))
 
+	cmd.Flags().String("listen", "", "address to listen on, either tcp://host:port or the path of the control socket; defaults to --socket")
+	_ = viper.BindPFlag("listen", cmd.Flags().Lookup("listen"))
+
+	cmd.Flags().Bool("insecure", false, "allow to listen on a tcp:// address other than loopback without requiring client certificates (--tls-ca); every client able to connect can execute arbitrary code on this host")
+	_ = viper.BindPFlag("insecure", cmd.Flags().Lookup("insecure"))
+
+	cmd.Flags().Int("pool-size", 2, "maximum number of Logstash instances, which are used to execute test sessions in parallel")
+	_ = viper.BindPFlag("pool-size", cmd.Flags().Lookup("pool-size"))
+
 	// TODO: Move default values to some sort of global lookup like defaultKeptEnvVars.
 	// TODO: Not yet sure, if this should be global or only in standalone.
 	cmd.Flags().StringSlice("keep-env", nil, "Add this environment variable to the list of variables that will be preserved from the calling process's environment.")
@@ -45,7 +55,10 @@ func makeDaemonStartCmd() *cobra.Command {
 }
 
 func runDaemonStart(_ *cobra.Command, _ []string) error {
-	socket := viper.GetString("socket")
+	address := viper.GetString("listen")
+	if address == "" {
+		address = viper.GetString("socket")
+	}
 	logstashPath := viper.GetString("logstash.path")
 	keptEnvs := viper.GetStringSlice("keep-envs")
 	if len(viper.GetStringSlice("daemon-keep-envs")) > 0 {
@@ -56,12 +69,24 @@ func runDaemonStart(_ *cobra.Command, _ []string) error {
 	waitForStateTimeout := viper.GetDuration("wait-for-state-timeout")
 	noCleanup := viper.GetBool("no-cleanup")
 	waitForLateArrivalsTimeout := viper.GetDuration("wait-for-late-arrivals-timeout")
+	poolSize := viper.GetInt("pool-size")
 	log := viper.Get("logger").(logging.Logger)
 
-	log.Debugf("config: socket: %s", socket)
+	tlsConfig, err := tlsFiles(viper.GetString).ServerConfig()
+	if err != nil {
+		return err
+	}
+	if !viper.GetBool("insecure") {
+		err = transport.CheckListenAddress(address, tlsConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Debugf("config: listen: %s", address)
 	log.Debugf("config: logstash-path: %s", logstashPath)
 
-	s := daemon.New(socket, logstashPath, keptEnvs, log, inflightShutdownTimeout, shutdownTimeout, waitForStateTimeout, noCleanup, waitForLateArrivalsTimeout)
+	s := daemon.New(address, logstashPath, keptEnvs, log, inflightShutdownTimeout, shutdownTimeout, waitForStateTimeout, noCleanup, waitForLateArrivalsTimeout, poolSize, tlsConfig)
 	defer s.Cleanup()
 
 	return s.Run(context.Background())
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/app/daemon/status"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
+)
+
+func makeDaemonStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show status of logstash-filter-verifier daemon",
+		RunE:  runDaemonStatus,
+	}
+
+	return cmd
+}
+
+func runDaemonStatus(_ *cobra.Command, _ []string) error {
+	tlsConfig, err := tlsFiles(viper.GetString).ClientConfig()
+	if err != nil {
+		return err
+	}
+
+	s := status.New(daemonAddress(viper.GetString), tlsConfig, viper.Get("logger").(logging.Logger))
+
+	return s.Run()
+}
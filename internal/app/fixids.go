@@ -0,0 +1,56 @@
+package app
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/app/lint"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
+)
+
+func makeFixIDsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fix-ids [<flags>] [<config>...]",
+		Short: "Add stable IDs to the plugins without ID in the Logstash configs",
+		Long: `Add stable IDs to the plugins without ID in the Logstash configs.
+
+The IDs are of the form <file>_<plugin>_<n>, e.g. main_mutate_2 for the
+second mutate plugin in main.conf, and are written back into the Logstash
+config files. Apart from the added IDs, the files are kept as they are,
+including formatting and comments.
+
+Each config argument is either a Logstash config file or a directory
+containing Logstash config files and is treated as a pipeline of its own.
+Alternatively, the pipelines of a pipelines.yml are processed with
+--pipeline. The IDs are unique within each pipeline.`,
+		RunE: runFixIDs,
+	}
+
+	cmd.Flags().StringP("pipeline", "p", "", "location of the pipelines.yml file to be processed (e.g. /etc/logstash/pipelines.yml)")
+	_ = viper.BindPFlag("fix-ids-pipeline", cmd.Flags().Lookup("pipeline"))
+	cmd.Flags().String("pipeline-base", "", "base directory for relative paths in the pipelines.yml")
+	_ = viper.BindPFlag("fix-ids-pipeline-base", cmd.Flags().Lookup("pipeline-base"))
+	cmd.Flags().Bool("dry-run", false, "only print the IDs to be added without changing the Logstash config files")
+	_ = viper.BindPFlag("fix-ids-dry-run", cmd.Flags().Lookup("dry-run"))
+
+	return cmd
+}
+
+func runFixIDs(cmd *cobra.Command, args []string) error {
+	pipeline := viper.GetString("fix-ids-pipeline")
+	if pipeline == "" && len(args) == 0 {
+		return errors.New("either --pipeline or at least one Logstash config is required, try --help")
+	}
+
+	f := lint.NewFixIDs(
+		args,
+		pipeline,
+		viper.GetString("fix-ids-pipeline-base"),
+		viper.GetBool("fix-ids-dry-run"),
+		cmd.OutOrStdout(),
+		viper.Get("logger").(logging.Logger),
+	)
+
+	return f.Run()
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/app/daemon/run"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
+)
+
+func makeGenerateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate [<flags>] <logfile>",
+		Short: "Generate a test case file from a file of log lines with logstash-filter-verifier daemon",
+		Long: `Generate a test case file from a file of log lines with logstash-filter-verifier daemon.
+
+Each line of the log file becomes a test case. The line is processed by
+the Logstash config and the events emitted by Logstash become the expected
+events of the test case. The generated test case file is written in YAML.`,
+		RunE: runGenerate,
+		Args: cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().StringP("socket", "s", "", "location of the control socket")
+	_ = viper.BindPFlag("generate-socket", cmd.Flags().Lookup("socket"))
+	cmd.Flags().String("daemon-address", "", "address of the daemon to connect to, either tcp://host:port or the path of the control socket; overrides --socket")
+	_ = viper.BindPFlag("generate-daemon-address", cmd.Flags().Lookup("daemon-address"))
+	cmd.Flags().String("tls-cert", "", "PEM encoded client certificate for TCP connections")
+	_ = viper.BindPFlag("generate-tls-cert", cmd.Flags().Lookup("tls-cert"))
+	cmd.Flags().String("tls-key", "", "PEM encoded private key belonging to --tls-cert")
+	_ = viper.BindPFlag("generate-tls-key", cmd.Flags().Lookup("tls-key"))
+	cmd.Flags().String("tls-ca", "", "PEM encoded CA certificate to verify the daemon")
+	_ = viper.BindPFlag("generate-tls-ca", cmd.Flags().Lookup("tls-ca"))
+	cmd.Flags().StringP("pipeline", "p", "", "location of the pipelines.yml file to be processed (e.g. /etc/logstash/pipelines.yml)")
+	_ = viper.BindPFlag("generate-pipeline", cmd.Flags().Lookup("pipeline"))
+	cmd.Flags().String("pipeline-base", "", "base directory for relative paths in the pipelines.yml")
+	_ = viper.BindPFlag("generate-pipeline-base", cmd.Flags().Lookup("pipeline-base"))
+	cmd.Flags().String("logstash-config", "", "path of the Logstash config for use, if no pipelines.yml exists (mutual exclusive with --pipeline flag).")
+	_ = viper.BindPFlag("generate-logstash-config", cmd.Flags().Lookup("logstash-config"))
+	cmd.Flags().String("plugin-mock", "", "path to a yaml file containing the definition for the plugin mocks.")
+	_ = viper.BindPFlag("generate-plugin-mock", cmd.Flags().Lookup("plugin-mock"))
+	cmd.Flags().String("metadata-key", "", "Key under which the content of the `@metadata` field is exposed in the returned events.")
+	_ = viper.BindPFlag("generate-metadata-key", cmd.Flags().Lookup("metadata-key"))
+	cmd.Flags().Bool("add-missing-id", false, "add implicit id for the plugins in the Logstash config if they are missing")
+	_ = viper.BindPFlag("generate-add-missing-id", cmd.Flags().Lookup("add-missing-id"))
+	cmd.Flags().String("input-plugin", "", "ID of the input plugin in the Logstash config, where the log lines are coming from")
+	_ = viper.BindPFlag("generate-input-plugin", cmd.Flags().Lookup("input-plugin"))
+	cmd.Flags().StringSlice("ignore", []string{"@timestamp"}, "fields to remove from the events and to list in the ignore section of the test case file; may be given multiple times")
+	_ = viper.BindPFlag("generate-ignore", cmd.Flags().Lookup("ignore"))
+	cmd.Flags().StringP("output", "o", "", "location of the test case file to be written")
+	_ = viper.BindPFlag("generate-output", cmd.Flags().Lookup("output"))
+	cmd.Flags().Bool("force", false, "overwrite the test case file, if it already exists")
+	_ = viper.BindPFlag("generate-force", cmd.Flags().Lookup("force"))
+
+	return cmd
+}
+
+func runGenerate(_ *cobra.Command, args []string) error {
+	log := viper.Get("logger").(logging.Logger)
+	pipeline := generateString("pipeline")
+	pipelineBase := generateString("pipeline-base")
+	logstashConfig := generateString("logstash-config")
+	pluginMock := generateString("plugin-mock")
+	metadataKey := generateString("metadata-key")
+	if metadataKey == "" {
+		metadataKey = "@metadata"
+	}
+	addMissingID := viper.GetBool("generate-add-missing-id") || viper.GetBool("add-missing-id")
+	inputPlugin := viper.GetString("generate-input-plugin")
+	ignore := viper.GetStringSlice("generate-ignore")
+	output := viper.GetString("generate-output")
+	force := viper.GetBool("generate-force")
+
+	if pipeline != "" && logstashConfig != "" {
+		return errors.New("--pipeline and --logstash-config flags are mutual exclusive")
+	}
+	if inputPlugin == "" {
+		return errors.New("--input-plugin flag is required")
+	}
+	if output == "" {
+		return errors.New("--output flag is required")
+	}
+
+	tlsConfig, err := tlsFiles(generateString).ClientConfig()
+	if err != nil {
+		return err
+	}
+
+	t, err := run.New(run.Options{
+		Address:        daemonAddress(generateString),
+		TLSConfig:      tlsConfig,
+		Pipeline:       pipeline,
+		PipelineBase:   pipelineBase,
+		LogstashConfig: logstashConfig,
+		PluginMock:     pluginMock,
+		MetadataKey:    metadataKey,
+		AddMissingID:   addMissingID,
+	}, log)
+	if err != nil {
+		return err
+	}
+
+	return t.Generate(inputPlugin, args[0], ignore, output, force)
+}
+
+// generateString returns the value of the flag of the generate command or,
+// if not set, the global value for key, e.g. from the config file.
+func generateString(key string) string {
+	if value := viper.GetString("generate-" + key); value != "" {
+		return value
+	}
+	return viper.GetString(key)
+}
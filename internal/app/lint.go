@@ -0,0 +1,68 @@
+package app
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/app/lint"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
+)
+
+func makeLintCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint [<flags>] [<config>...]",
+		Short: "Check Logstash configs for common problems without starting Logstash",
+		Long: `Check Logstash configs for common problems without starting Logstash.
+
+Each config argument is either a Logstash config file or a directory
+containing Logstash config files and is checked as a pipeline of its own.
+Alternatively, the pipelines of a pipelines.yml are checked with --pipeline.
+
+The following rules are checked:
+  syntax              the Logstash config can not be parsed
+  missing-id          plugins without id
+  duplicate-id        plugin ids, which are not unique within a pipeline
+  unreachable-branch  branches, which are never executed
+  undefined-field     fields referenced in conditions, which are never set
+  deprecated-option   deprecated plugin options
+  unbalanced-tag      tags removed, but never added
+
+The command fails, if problems of level error or warning are found.`,
+		RunE: runLint,
+	}
+
+	cmd.Flags().StringP("pipeline", "p", "", "location of the pipelines.yml file to be checked (e.g. /etc/logstash/pipelines.yml)")
+	_ = viper.BindPFlag("lint-pipeline", cmd.Flags().Lookup("pipeline"))
+	cmd.Flags().String("pipeline-base", "", "base directory for relative paths in the pipelines.yml")
+	_ = viper.BindPFlag("lint-pipeline-base", cmd.Flags().Lookup("pipeline-base"))
+	cmd.Flags().String("format", lint.FormatText, "output format of the findings, either text or sarif")
+	_ = viper.BindPFlag("lint-format", cmd.Flags().Lookup("format"))
+	cmd.Flags().StringSlice("disable", nil, "rules to be disabled; may be given multiple times")
+	_ = viper.BindPFlag("lint-disable", cmd.Flags().Lookup("disable"))
+
+	return cmd
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	pipeline := viper.GetString("lint-pipeline")
+	if pipeline == "" && len(args) == 0 {
+		return errors.New("either --pipeline or at least one Logstash config is required, try --help")
+	}
+
+	l, err := lint.New(
+		args,
+		pipeline,
+		viper.GetString("lint-pipeline-base"),
+		viper.GetString("lint-format"),
+		viper.GetStringSlice("lint-disable"),
+		cmd.Root().Version,
+		cmd.OutOrStdout(),
+		viper.Get("logger").(logging.Logger),
+	)
+	if err != nil {
+		return err
+	}
+
+	return l.Run()
+}
@@ -0,0 +1,678 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	config "github.com/breml/logstash-config"
+	"github.com/breml/logstash-config/ast"
+)
+
+// Level is the severity of a finding. The values correspond to the levels
+// used by SARIF.
+type Level string
+
+const (
+	LevelError   Level = "error"
+	LevelWarning Level = "warning"
+	LevelNote    Level = "note"
+)
+
+// Rule describes a check performed by the linter.
+type Rule struct {
+	ID          string
+	Level       Level
+	Description string
+}
+
+// Rules contains all the checks performed by the linter.
+var Rules = []Rule{
+	{ID: "syntax", Level: LevelError, Description: "The Logstash config can not be parsed."},
+	{ID: "missing-id", Level: LevelError, Description: "Plugins need an id, otherwise the Logstash config can not be tested with logstash-filter-verifier."},
+	{ID: "duplicate-id", Level: LevelError, Description: "Plugin ids need to be unique within a pipeline."},
+	{ID: "unreachable-branch", Level: LevelWarning, Description: "Branches following an always true or an already checked condition are never executed."},
+	{ID: "undefined-field", Level: LevelNote, Description: "Fields referenced in conditions should be set somewhere in the pipeline."},
+	{ID: "deprecated-option", Level: LevelWarning, Description: "Deprecated plugin options should be replaced."},
+	{ID: "unbalanced-tag", Level: LevelWarning, Description: "Tags removed with remove_tag should be added somewhere in the pipeline and not by the same plugin."},
+}
+
+func rule(id string) Rule {
+	for _, r := range Rules {
+		if r.ID == id {
+			return r
+		}
+	}
+	panic(fmt.Sprintf("unknown lint rule %q", id))
+}
+
+// Finding is a problem found in a Logstash config file. Line and Column
+// are 0, if the position of the problem is not known.
+type Finding struct {
+	Rule    string
+	Level   Level
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// File is a Logstash config file.
+type File struct {
+	Name string
+	Body []byte
+}
+
+// Check parses the Logstash config files of a single pipeline and returns
+// the problems found, ordered by file and position.
+func Check(files []File) []Finding {
+	c := checker{
+		addedTags: map[string]bool{},
+	}
+
+	for _, f := range files {
+		c.file = f.Name
+
+		icfg, err := config.Parse(f.Name, f.Body)
+		if err != nil {
+			pos, msg := parseError(err)
+			c.report("syntax", pos, "%s", msg)
+			continue
+		}
+		cfg, ok := icfg.(ast.Config)
+		if !ok {
+			c.report("syntax", ast.Pos{}, "not a valid Logstash config")
+			continue
+		}
+
+		for _, sections := range [][]ast.PluginSection{cfg.Input, cfg.Filter, cfg.Output} {
+			for _, section := range sections {
+				c.section = section.PluginType
+				c.block(section.BranchOrPlugins)
+			}
+		}
+	}
+
+	c.checkIDs()
+	c.checkFields()
+	c.checkTags()
+
+	order := map[string]int{}
+	for i, f := range files {
+		order[f.Name] = i
+	}
+	sort.SliceStable(c.findings, func(i, j int) bool {
+		a, b := c.findings[i], c.findings[j]
+		if a.File != b.File {
+			return order[a.File] < order[b.File]
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	return c.findings
+}
+
+// parseErrorRe matches the errors of the parser, e.g.
+// main.conf:7:14 (180): rule plugin: Parsing error ...
+var parseErrorRe = regexp.MustCompile(`(?s)^.*?:(\d+):(\d+) \(\d+\): (?:rule \w+: )?(.*)$`)
+
+// parseError returns the position and the message of a parse error.
+func parseError(err error) (ast.Pos, string) {
+	m := parseErrorRe.FindStringSubmatch(err.Error())
+	if m == nil {
+		return ast.Pos{}, strings.Join(strings.Fields(err.Error()), " ")
+	}
+	line, _ := strconv.Atoi(m[1])
+	column, _ := strconv.Atoi(m[2])
+	return ast.Pos{Line: line, Column: column}, strings.Join(strings.Fields(m[3]), " ")
+}
+
+type plugin struct {
+	file    string
+	section ast.PluginType
+	plugin  ast.Plugin
+}
+
+// reference is a field or a tag referenced at a given position.
+type reference struct {
+	file string
+	pos  ast.Pos
+	name string
+}
+
+type checker struct {
+	findings []Finding
+	file     string
+	section  ast.PluginType
+
+	plugins []plugin
+
+	setFields  []string
+	references []reference
+	// dynamic is true, if the pipeline contains inputs or filters, which
+	// set fields not known in advance (e.g. json codec, kv filter).
+	dynamic bool
+
+	addedTags   map[string]bool
+	removedTags []reference
+	dynamicTags bool
+}
+
+func (c *checker) report(ruleID string, pos ast.Pos, format string, a ...interface{}) {
+	c.findings = append(c.findings, Finding{
+		Rule:    ruleID,
+		Level:   rule(ruleID).Level,
+		File:    c.file,
+		Line:    pos.Line,
+		Column:  pos.Column,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+func (c *checker) block(bops []ast.BranchOrPlugin) {
+	for _, bop := range bops {
+		switch node := bop.(type) {
+		case ast.Plugin:
+			c.plugin(node)
+		case ast.Branch:
+			c.branch(node)
+		}
+	}
+}
+
+func (c *checker) branch(branch ast.Branch) {
+	type checked struct {
+		condition string
+		line      int
+	}
+	var conditions []checked
+	alwaysTrue := 0
+
+	block := func(label string, pos ast.Pos, condition *ast.Condition, bops []ast.BranchOrPlugin) {
+		switch {
+		case alwaysTrue > 0:
+			c.report("unreachable-branch", pos, "%s branch is unreachable, the condition at line %d is always true", label, alwaysTrue)
+		case condition != nil:
+			for _, previous := range conditions {
+				if previous.condition == condition.String() {
+					c.report("unreachable-branch", pos, "%s branch is unreachable, the condition %s is already checked at line %d", label, condition.String(), previous.line)
+					break
+				}
+			}
+		}
+
+		if condition != nil {
+			c.condition(*condition, pos)
+			conditions = append(conditions, checked{condition: condition.String(), line: pos.Line})
+			if alwaysTrue == 0 && isAlwaysTrue(*condition) {
+				alwaysTrue = pos.Line
+			}
+		}
+
+		c.block(bops)
+	}
+
+	block("if", branch.IfBlock.Pos(), &branch.IfBlock.Condition, branch.IfBlock.Block)
+	for _, elseIf := range branch.ElseIfBlock {
+		elseIf := elseIf
+		block("else if", elseIf.Pos(), &elseIf.Condition, elseIf.Block)
+	}
+	if branch.ElseBlock.Pos().Line > 0 || len(branch.ElseBlock.Block) > 0 {
+		block("else", branch.ElseBlock.Pos(), nil, branch.ElseBlock.Block)
+	}
+}
+
+// isAlwaysTrue returns true, if the condition consists only of a string
+// or number literal, which is always truthy in Logstash.
+func isAlwaysTrue(condition ast.Condition) bool {
+	if len(condition.Expression) != 1 {
+		return false
+	}
+	switch expr := condition.Expression[0].(type) {
+	case ast.ConditionExpression:
+		return isAlwaysTrue(expr.Condition)
+	case ast.RvalueExpression:
+		switch expr.RValue.(type) {
+		case ast.StringAttribute, ast.NumberAttribute:
+			return true
+		}
+	}
+	return false
+}
+
+func (c *checker) condition(condition ast.Condition, pos ast.Pos) {
+	for _, expression := range condition.Expression {
+		switch expr := expression.(type) {
+		case ast.ConditionExpression:
+			c.condition(expr.Condition, pos)
+		case ast.NegativeConditionExpression:
+			c.condition(expr.Condition, pos)
+		case ast.NegativeSelectorExpression:
+			c.rvalue(expr.Selector, pos)
+		case ast.InExpression:
+			c.rvalue(expr.LValue, pos)
+			c.rvalue(expr.RValue, pos)
+		case ast.NotInExpression:
+			c.rvalue(expr.LValue, pos)
+			c.rvalue(expr.RValue, pos)
+		case ast.RvalueExpression:
+			c.rvalue(expr.RValue, pos)
+		case ast.CompareExpression:
+			c.rvalue(expr.LValue, pos)
+			c.rvalue(expr.RValue, pos)
+		case ast.RegexpExpression:
+			c.rvalue(expr.LValue, pos)
+		}
+	}
+}
+
+// rvalue records the field referenced by a selector. pos is the position
+// of the branch, which is used, if the position of the selector is not
+// known.
+func (c *checker) rvalue(rvalue ast.Rvalue, pos ast.Pos) {
+	selector, ok := rvalue.(ast.Selector)
+	if !ok {
+		return
+	}
+	if selector.Pos().Line > 0 {
+		pos = selector.Pos()
+	}
+	c.references = append(c.references, reference{file: c.file, pos: pos, name: selector.String()})
+}
+
+// defaultTags are the tags added by the plugins in case of a failure.
+var defaultTags = map[string][]string{
+	"csv":            {"_csvparsefailure"},
+	"date":           {"_dateparsefailure"},
+	"dissect":        {"_dissectfailure"},
+	"elasticsearch":  {"_elasticsearch_lookup_failure"},
+	"geoip":          {"_geoip_lookup_failure"},
+	"grok":           {"_grokparsefailure", "_groktimeout"},
+	"http":           {"_httprequestfailure"},
+	"jdbc_streaming": {"_jdbcstreamingfailure"},
+	"json":           {"_jsonparsefailure"},
+	"kv":             {"_kv_filter_error", "_kv_filter_timeout"},
+	"mutate":         {"_mutate_error"},
+	"ruby":           {"_rubyexception"},
+	"split":          {"_split_type_failure"},
+	"xml":            {"_xmlparsefailure"},
+}
+
+func (c *checker) plugin(p ast.Plugin) {
+	c.plugins = append(c.plugins, plugin{file: c.file, section: c.section, plugin: p})
+
+	if c.section != ast.Output {
+		for _, tag := range defaultTags[p.Name()] {
+			c.addedTags[tag] = true
+		}
+	}
+
+	added := map[string]bool{}
+	if attr, ok := attribute(p, "add_tag"); ok {
+		for _, tag := range stringValues(attr) {
+			added[tag] = true
+		}
+	}
+
+	for _, attr := range p.Attributes {
+		if attr == nil {
+			continue
+		}
+		c.deprecated(p, attr)
+		if c.section == ast.Output {
+			continue
+		}
+
+		switch name := attr.Name(); {
+		case name == "add_field":
+			for _, key := range hashKeys(attr) {
+				c.set(key)
+			}
+		case name == "target" || name == "destination":
+			for _, value := range stringValues(attr) {
+				c.set(value)
+			}
+		case name == "add_tag" || name == "tags" || strings.HasPrefix(name, "tag_on_"):
+			for _, tag := range stringValues(attr) {
+				if strings.Contains(tag, "%{") {
+					c.dynamicTags = true
+					continue
+				}
+				c.addedTags[tag] = true
+			}
+		case name == "remove_tag":
+			for _, tag := range stringValues(attr) {
+				if added[tag] {
+					c.report("unbalanced-tag", attr.Pos(), "tag %q is added and removed by the same plugin", tag)
+				}
+				c.removedTags = append(c.removedTags, reference{file: c.file, pos: attr.Pos(), name: tag})
+			}
+		}
+	}
+
+	switch c.section {
+	case ast.Input:
+		c.input(p)
+	case ast.Filter:
+		c.filter(p)
+	}
+}
+
+// lineInputs are the input plugins, which only set well known fields,
+// unless a structured codec is used.
+var lineInputs = map[string]bool{
+	"exec":      true,
+	"file":      true,
+	"generator": true,
+	"heartbeat": true,
+	"stdin":     true,
+	"tcp":       true,
+	"udp":       true,
+	"unix":      true,
+}
+
+var lineCodecs = map[string]bool{
+	"line":      true,
+	"multiline": true,
+	"plain":     true,
+}
+
+func (c *checker) input(p ast.Plugin) {
+	if !lineInputs[p.Name()] {
+		c.dynamic = true
+		return
+	}
+
+	attr, ok := attribute(p, "codec")
+	if !ok {
+		return
+	}
+	codec := strings.Fields(attr.ValueString())
+	if len(codec) > 0 && !lineCodecs[strings.Trim(codec[0], `"'`)] {
+		c.dynamic = true
+	}
+}
+
+var (
+	grokSemanticRe     = regexp.MustCompile(`%\{\w+:([^:}]+)(?::\w+)?\}`)
+	grokNamedCaptureRe = regexp.MustCompile(`\(\?<([^>=!][^>]*)>`)
+	dissectFieldRe     = regexp.MustCompile(`%\{([^}]*)\}`)
+)
+
+func (c *checker) filter(p ast.Plugin) {
+	_, hasTarget := attribute(p, "target")
+
+	switch p.Name() {
+	case "mutate":
+		for _, option := range []string{"rename", "copy"} {
+			if attr, ok := attribute(p, option); ok {
+				for _, value := range hashValues(attr) {
+					c.set(value)
+				}
+			}
+		}
+		for _, option := range []string{"replace", "merge", "split"} {
+			if attr, ok := attribute(p, option); ok {
+				for _, key := range hashKeys(attr) {
+					c.set(key)
+				}
+			}
+		}
+	case "grok":
+		if attr, ok := attribute(p, "match"); ok {
+			for _, pattern := range hashValues(attr) {
+				for _, m := range grokSemanticRe.FindAllStringSubmatch(pattern, -1) {
+					c.set(m[1])
+				}
+				for _, m := range grokNamedCaptureRe.FindAllStringSubmatch(pattern, -1) {
+					c.set(m[1])
+				}
+			}
+		}
+	case "dissect":
+		if attr, ok := attribute(p, "mapping"); ok {
+			for _, pattern := range hashValues(attr) {
+				for _, m := range dissectFieldRe.FindAllStringSubmatch(pattern, -1) {
+					c.dissectField(m[1])
+				}
+			}
+		}
+	case "csv":
+		if attr, ok := attribute(p, "columns"); ok {
+			for _, column := range stringValues(attr) {
+				c.set(column)
+			}
+		} else if !hasTarget {
+			c.dynamic = true
+		}
+	case "geoip":
+		if !hasTarget {
+			c.set("geoip")
+		}
+	case "translate":
+		if _, ok := attribute(p, "destination"); !ok && !hasTarget {
+			c.set("translation")
+		}
+	case "fingerprint":
+		if !hasTarget {
+			c.set("fingerprint")
+		}
+	case "clone":
+		c.set("type")
+	case "json", "kv", "useragent", "xml":
+		if !hasTarget {
+			c.dynamic = true
+		}
+	case "aggregate", "de_dot", "elasticsearch", "jdbc_static", "memcached", "ruby":
+		c.dynamic = true
+	}
+}
+
+// dissectField records the field set by a dissect key, e.g. +field, which
+// appends to the field, or ?name, which is skipped.
+func (c *checker) dissectField(key string) {
+	if strings.HasPrefix(key, "&") {
+		c.dynamic = true
+		return
+	}
+	if key == "" || strings.HasPrefix(key, "?") || strings.HasPrefix(key, "*") {
+		return
+	}
+	key = strings.TrimPrefix(key, "+")
+	key = strings.TrimSuffix(key, "->")
+	if i := strings.LastIndex(key, "/"); i > 0 {
+		key = key[:i]
+	}
+	c.set(key)
+}
+
+func (c *checker) set(field string) {
+	if strings.Contains(field, "%{") {
+		c.dynamic = true
+		return
+	}
+	if field = fieldName(field); field != "" {
+		c.setFields = append(c.setFields, field)
+	}
+}
+
+// fieldName returns the field in the bracket notation, e.g. [field].
+func fieldName(field string) string {
+	field = strings.TrimSpace(field)
+	if field == "" || strings.HasPrefix(field, "[") {
+		return field
+	}
+	return "[" + field + "]"
+}
+
+func attribute(p ast.Plugin, name string) (ast.Attribute, bool) {
+	for _, attr := range p.Attributes {
+		if attr != nil && attr.Name() == name {
+			return attr, true
+		}
+	}
+	return nil, false
+}
+
+// stringValues returns the values of a string attribute or of an array of
+// strings.
+func stringValues(attr ast.Attribute) []string {
+	switch a := attr.(type) {
+	case ast.StringAttribute:
+		return []string{a.Value()}
+	case ast.ArrayAttribute:
+		var values []string
+		for _, element := range a.Attributes {
+			values = append(values, stringValues(element)...)
+		}
+		return values
+	}
+	return nil
+}
+
+func hashKeys(attr ast.Attribute) []string {
+	hash, ok := attr.(ast.HashAttribute)
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(hash.Entries))
+	for _, entry := range hash.Entries {
+		if key, ok := entry.Key.(ast.StringAttribute); ok {
+			keys = append(keys, key.Value())
+		}
+	}
+	return keys
+}
+
+func hashValues(attr ast.Attribute) []string {
+	hash, ok := attr.(ast.HashAttribute)
+	if !ok {
+		return nil
+	}
+	var values []string
+	for _, entry := range hash.Entries {
+		values = append(values, stringValues(entry.Value)...)
+	}
+	return values
+}
+
+// deprecatedOptions contains per plugin (<section>/<name>) the deprecated
+// options and their replacements. An empty replacement means, the option
+// is going to be removed without a replacement.
+var deprecatedOptions = map[string]map[string]string{
+	"input/beats": {
+		"ssl":               "ssl_enabled",
+		"ssl_peer_metadata": "enrich",
+		"ssl_verify_mode":   "ssl_client_authentication",
+	},
+	"input/elasticsearch": {
+		"ca_file": "ssl_certificate_authorities",
+		"ssl":     "ssl_enabled",
+	},
+	"input/http": {
+		"keystore":    "ssl_keystore_path",
+		"ssl":         "ssl_enabled",
+		"verify_mode": "ssl_client_authentication",
+	},
+	"input/tcp": {
+		"ssl_cert":   "ssl_certificate",
+		"ssl_enable": "ssl_enabled",
+		"ssl_verify": "ssl_client_authentication",
+	},
+	"filter/elasticsearch": {
+		"ca_file":  "ssl_certificate_authorities",
+		"keystore": "ssl_keystore_path",
+		"ssl":      "ssl_enabled",
+	},
+	"filter/translate": {
+		"destination": "target",
+		"field":       "source",
+	},
+	"output/elasticsearch": {
+		"cacert":                       "ssl_certificate_authorities",
+		"document_type":                "",
+		"keystore":                     "ssl_keystore_path",
+		"ssl":                          "ssl_enabled",
+		"ssl_certificate_verification": "ssl_verification_mode",
+		"truststore":                   "ssl_truststore_path",
+	},
+}
+
+func (c *checker) deprecated(p ast.Plugin, attr ast.Attribute) {
+	replacement, ok := deprecatedOptions[c.section.String()+"/"+p.Name()][attr.Name()]
+	if !ok {
+		return
+	}
+	if replacement == "" {
+		c.report("deprecated-option", attr.Pos(), "option %q of the %s %s plugin is deprecated", attr.Name(), p.Name(), c.section)
+		return
+	}
+	c.report("deprecated-option", attr.Pos(), "option %q of the %s %s plugin is deprecated, use %q instead", attr.Name(), p.Name(), c.section, replacement)
+}
+
+func (c *checker) checkIDs() {
+	ids := map[string]plugin{}
+	for _, p := range c.plugins {
+		c.file = p.file
+		id, err := p.plugin.ID()
+		if err != nil {
+			c.report("missing-id", p.plugin.Pos(), "%s %s plugin has no id", p.plugin.Name(), p.section)
+			continue
+		}
+		if first, ok := ids[id]; ok {
+			c.report("duplicate-id", p.plugin.Pos(), "plugin id %q is not unique, it is already used at %s:%d", id, first.file, first.plugin.Pos().Line)
+			continue
+		}
+		ids[id] = p
+	}
+}
+
+// builtinFields are the fields, which are present in most of the events
+// without being set in the pipeline.
+var builtinFields = []string{"[@timestamp]", "[@version]", "[event]", "[host]", "[log]", "[message]", "[tags]", "[type]"}
+
+func (c *checker) checkFields() {
+	if c.dynamic {
+		return
+	}
+
+	reported := map[string]bool{}
+	for _, ref := range c.references {
+		if reported[ref.name] || isSet(ref.name, builtinFields) || isSet(ref.name, c.setFields) {
+			continue
+		}
+		reported[ref.name] = true
+		c.file = ref.file
+		c.report("undefined-field", ref.pos, "field %s is referenced in a condition, but never set in the pipeline", ref.name)
+	}
+}
+
+// isSet returns true, if field or one of its parent or child fields is
+// contained in fields.
+func isSet(field string, fields []string) bool {
+	for _, f := range fields {
+		if strings.HasPrefix(field, f) || strings.HasPrefix(f, field) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *checker) checkTags() {
+	if c.dynamic || c.dynamicTags {
+		return
+	}
+
+	for _, ref := range c.removedTags {
+		if strings.Contains(ref.name, "%{") || c.addedTags[ref.name] {
+			continue
+		}
+		c.file = ref.file
+		c.report("unbalanced-tag", ref.pos, "tag %q is removed, but never added in the pipeline", ref.name)
+	}
+}
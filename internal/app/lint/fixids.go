@@ -0,0 +1,278 @@
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	config "github.com/breml/logstash-config"
+	"github.com/breml/logstash-config/ast"
+	"github.com/pkg/errors"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
+)
+
+// Fix is an ID added to a plugin of a Logstash config file.
+type Fix struct {
+	File   string
+	Line   int
+	Column int
+	Plugin string
+	ID     string
+}
+
+// AddMissingIDs adds an ID of the form <file>_<plugin>_<n> to the plugins
+// without ID in the Logstash config files of a pipeline, where <n> counts
+// the plugins of the same name within the file. Only the IDs are inserted
+// into the files, the remaining content (formatting, comments) is kept as
+// is. The IDs are stable, the same Logstash config files result in the
+// same IDs.
+func AddMissingIDs(files []File) ([]File, []Fix, error) {
+	configs := make([]ast.Config, 0, len(files))
+	ids := map[string]bool{}
+	for _, f := range files {
+		cfg, err := parse(f)
+		if err != nil {
+			return nil, nil, err
+		}
+		configs = append(configs, cfg)
+
+		for _, p := range plugins(cfg) {
+			if id, err := p.ID(); err == nil {
+				ids[id] = true
+			}
+		}
+	}
+
+	fixed := make([]File, 0, len(files))
+	var fixes []Fix
+	for i, f := range files {
+		var missing []ast.Plugin
+		counts := map[string]int{}
+		prefix := idSafe(strings.TrimSuffix(filepath.Base(f.Name), filepath.Ext(f.Name)))
+		for _, p := range plugins(configs[i]) {
+			counts[p.Name()]++
+			if _, ok := attribute(p, "id"); ok {
+				continue
+			}
+
+			n := counts[p.Name()]
+			id := fmt.Sprintf("%s_%s_%d", prefix, idSafe(p.Name()), n)
+			for ids[id] {
+				n++
+				id = fmt.Sprintf("%s_%s_%d", prefix, idSafe(p.Name()), n)
+			}
+			ids[id] = true
+
+			missing = append(missing, p)
+			fixes = append(fixes, Fix{
+				File:   f.Name,
+				Line:   p.Pos().Line,
+				Column: p.Pos().Column,
+				Plugin: p.Name(),
+				ID:     id,
+			})
+		}
+
+		body := f.Body
+		// Insert the IDs from the end of the file, such that the offsets
+		// of the remaining plugins stay valid.
+		fileFixes := fixes[len(fixes)-len(missing):]
+		for j := len(missing) - 1; j >= 0; j-- {
+			var err error
+			body, err = insertID(body, missing[j].Pos().Offset, fileFixes[j].ID)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "%s:%d", f.Name, missing[j].Pos().Line)
+			}
+		}
+
+		fixedFile := File{Name: f.Name, Body: body}
+		if len(missing) > 0 {
+			// Make sure, the Logstash config is still valid.
+			_, err := parse(fixedFile)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "failed to add the missing IDs")
+			}
+		}
+		fixed = append(fixed, fixedFile)
+	}
+
+	return fixed, fixes, nil
+}
+
+func parse(f File) (ast.Config, error) {
+	icfg, err := config.Parse(f.Name, f.Body)
+	if err != nil {
+		return ast.Config{}, err
+	}
+	cfg, ok := icfg.(ast.Config)
+	if !ok {
+		return ast.Config{}, errors.Errorf("%s: not a valid Logstash config", f.Name)
+	}
+	return cfg, nil
+}
+
+// plugins returns the plugins of the Logstash config in the order of their
+// appearance.
+func plugins(cfg ast.Config) []ast.Plugin {
+	var result []ast.Plugin
+	var block func(bops []ast.BranchOrPlugin)
+	block = func(bops []ast.BranchOrPlugin) {
+		for _, bop := range bops {
+			switch node := bop.(type) {
+			case ast.Plugin:
+				result = append(result, node)
+			case ast.Branch:
+				block(node.IfBlock.Block)
+				for _, elseIf := range node.ElseIfBlock {
+					block(elseIf.Block)
+				}
+				block(node.ElseBlock.Block)
+			}
+		}
+	}
+
+	for _, sections := range [][]ast.PluginSection{cfg.Input, cfg.Filter, cfg.Output} {
+		for _, section := range sections {
+			block(section.BranchOrPlugins)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Pos().Offset < result[j].Pos().Offset
+	})
+	return result
+}
+
+var idUnsafeRe = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+func idSafe(s string) string {
+	return idUnsafeRe.ReplaceAllString(s, "_")
+}
+
+// insertID inserts the id attribute into the plugin starting at offset.
+// If the attributes of the plugin are on lines of their own, the id is
+// inserted on a new line with the same indentation, otherwise right after
+// the opening curly bracket.
+func insertID(body []byte, offset int, id string) ([]byte, error) {
+	open := bytes.IndexByte(body[offset:], '{')
+	if open < 0 {
+		return nil, errors.New("opening curly bracket of plugin not found")
+	}
+	open += offset + 1
+
+	attr := fmt.Sprintf("id => %q", id)
+
+	rest := bytes.TrimLeft(body[open:], " \t")
+	if len(rest) > 0 && rest[0] != '\n' && rest[0] != '\r' && rest[0] != '#' {
+		insert := " " + attr
+		// No whitespace between the opening curly bracket and the next token.
+		if open == len(body)-len(rest) {
+			insert += " "
+		}
+		return splice(body, open, insert), nil
+	}
+
+	// Multiline plugin, insert after the end of the line with the opening
+	// curly bracket (which might contain a comment).
+	eol := bytes.IndexByte(body[open:], '\n')
+	if eol < 0 {
+		return nil, errors.New("unexpected end of plugin")
+	}
+	eol += open
+
+	indent := indentation(body, offset) + "  "
+	if next := body[eol+1:]; len(bytes.TrimSpace(next)) > 0 {
+		line := next
+		if i := bytes.IndexByte(next, '\n'); i >= 0 {
+			line = next[:i]
+		}
+		if trimmed := bytes.TrimLeft(line, " \t"); len(trimmed) > 0 && trimmed[0] != '}' {
+			indent = string(line[:len(line)-len(trimmed)])
+		}
+	}
+
+	return splice(body, eol, "\n"+indent+attr), nil
+}
+
+// indentation returns the leading whitespace of the line containing offset.
+func indentation(body []byte, offset int) string {
+	start := bytes.LastIndexByte(body[:offset], '\n') + 1
+	line := body[start:offset]
+	return string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+}
+
+func splice(body []byte, at int, insert string) []byte {
+	result := make([]byte, 0, len(body)+len(insert))
+	result = append(result, body[:at]...)
+	result = append(result, insert...)
+	return append(result, body[at:]...)
+}
+
+type FixIDs struct {
+	paths        []string
+	pipeline     string
+	pipelineBase string
+	dryRun       bool
+	out          io.Writer
+	log          logging.Logger
+}
+
+// NewFixIDs creates a new fix-ids command, which adds the missing plugin
+// IDs to the Logstash config files.
+func NewFixIDs(paths []string, pipeline, pipelineBase string, dryRun bool, out io.Writer, log logging.Logger) FixIDs {
+	return FixIDs{
+		paths:        paths,
+		pipeline:     pipeline,
+		pipelineBase: pipelineBase,
+		dryRun:       dryRun,
+		out:          out,
+		log:          log,
+	}
+}
+
+// Run adds the missing plugin IDs and writes the Logstash config files,
+// which have been changed.
+func (f FixIDs) Run() error {
+	pipelines, err := ReadPipelines(f.paths, f.pipeline, f.pipelineBase)
+	if err != nil {
+		return err
+	}
+
+	for _, files := range pipelines {
+		fixed, fixes, err := AddMissingIDs(files)
+		if err != nil {
+			return err
+		}
+
+		for _, fix := range fixes {
+			fmt.Fprintf(f.out, "%s:%d:%d: %s plugin gets id %q\n", fix.File, fix.Line, fix.Column, fix.Plugin, fix.ID)
+		}
+
+		if f.dryRun {
+			continue
+		}
+
+		for i, file := range fixed {
+			if bytes.Equal(file.Body, files[i].Body) {
+				continue
+			}
+			fi, err := os.Stat(file.Name)
+			if err != nil {
+				return err
+			}
+			err = os.WriteFile(file.Name, file.Body, fi.Mode().Perm())
+			if err != nil {
+				return errors.Wrapf(err, "failed to write Logstash config %q", file.Name)
+			}
+			f.log.Debugf("wrote Logstash config %q", file.Name)
+		}
+	}
+
+	return nil
+}
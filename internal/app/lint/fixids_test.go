@@ -0,0 +1,106 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/app/lint"
+)
+
+func TestAddMissingIDs(t *testing.T) {
+	cases := []struct {
+		name  string
+		files []lint.File
+
+		want    []string
+		wantIDs []string
+		wantErr bool
+	}{
+		{
+			name: "single line and multiline plugins",
+			files: []lint.File{{Name: "conf.d/main.conf", Body: []byte(`input { stdin {} }
+filter {
+  # parse the line
+  grok {
+    match => { "message" => "%{WORD:verb}" }
+  }
+  if [verb] == "GET" {
+    mutate { add_tag => [ "get" ] }
+  } else {
+    drop { # comment
+    }
+  }
+}
+output { stdout { codec => rubydebug } }
+`)}},
+
+			want: []string{`input { stdin { id => "main_stdin_1" } }
+filter {
+  # parse the line
+  grok {
+    id => "main_grok_1"
+    match => { "message" => "%{WORD:verb}" }
+  }
+  if [verb] == "GET" {
+    mutate { id => "main_mutate_1" add_tag => [ "get" ] }
+  } else {
+    drop { # comment
+      id => "main_drop_1"
+    }
+  }
+}
+output { stdout { id => "main_stdout_1" codec => rubydebug } }
+`},
+			wantIDs: []string{"main_stdin_1", "main_grok_1", "main_mutate_1", "main_drop_1", "main_stdout_1"},
+		},
+		{
+			name: "existing ids are kept and not reused",
+			files: []lint.File{
+				{Name: "a.conf", Body: []byte("filter {\n  mutate { id => b_mutate_2 }\n}\n")},
+				{Name: "b.conf", Body: []byte("filter {\n  mutate {add_tag => \"x\"}\n  mutate {}\n}\n")},
+			},
+
+			want: []string{
+				"filter {\n  mutate { id => b_mutate_2 }\n}\n",
+				"filter {\n  mutate { id => \"b_mutate_1\" add_tag => \"x\"}\n  mutate { id => \"b_mutate_3\" }\n}\n",
+			},
+			wantIDs: []string{"b_mutate_1", "b_mutate_3"},
+		},
+		{
+			name:  "invalid config",
+			files: []lint.File{{Name: "invalid.conf", Body: []byte("filter {")}},
+
+			wantErr: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			fixed, fixes, err := lint.AddMissingIDs(test.files)
+			is.Equal(err != nil, test.wantErr) // error
+			if test.wantErr {
+				return
+			}
+
+			var got []string
+			for _, f := range fixed {
+				got = append(got, string(f.Body))
+			}
+			is.Equal(got, test.want)
+
+			var ids []string
+			for _, fix := range fixes {
+				ids = append(ids, fix.ID)
+			}
+			is.Equal(ids, test.wantIDs)
+
+			// Running again does not change anything.
+			_, fixes, err = lint.AddMissingIDs(fixed)
+			is.NoErr(err)
+			is.Equal(len(fixes), 0)
+		})
+	}
+}
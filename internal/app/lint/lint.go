@@ -0,0 +1,173 @@
+// Package lint checks Logstash configs for common problems without
+// starting Logstash.
+package lint
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bmatcuk/doublestar/v2"
+	"github.com/pkg/errors"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pipeline"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
+)
+
+type Lint struct {
+	paths        []string
+	pipeline     string
+	pipelineBase string
+	format       string
+	disabled     map[string]bool
+	version      string
+	out          io.Writer
+	log          logging.Logger
+}
+
+// New creates a new lint command. Each of the paths, either a Logstash
+// config file or a directory containing Logstash config files, is checked
+// as a pipeline of its own, as are the pipelines of the pipelines.yml.
+func New(paths []string, pipeline, pipelineBase, format string, disabled []string, version string, out io.Writer, log logging.Logger) (Lint, error) {
+	if format != FormatText && format != FormatSARIF {
+		return Lint{}, errors.Errorf("unsupported format %q, supported formats: %s, %s", format, FormatText, FormatSARIF)
+	}
+
+	disabledRules := make(map[string]bool, len(disabled))
+	for _, id := range disabled {
+		if !isRule(id) {
+			return Lint{}, errors.Errorf("unknown rule %q", id)
+		}
+		disabledRules[id] = true
+	}
+
+	return Lint{
+		paths:        paths,
+		pipeline:     pipeline,
+		pipelineBase: pipelineBase,
+		format:       format,
+		disabled:     disabledRules,
+		version:      version,
+		out:          out,
+		log:          log,
+	}, nil
+}
+
+func isRule(id string) bool {
+	for _, r := range Rules {
+		if r.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Run checks the Logstash configs and writes the findings. An error is
+// returned, if there are findings of level error or warning.
+func (l Lint) Run() error {
+	pipelines, err := ReadPipelines(l.paths, l.pipeline, l.pipelineBase)
+	if err != nil {
+		return err
+	}
+
+	var findings []Finding
+	for _, files := range pipelines {
+		l.log.Debugf("lint pipeline consisting of %d Logstash config files", len(files))
+		for _, f := range Check(files) {
+			if !l.disabled[f.Rule] {
+				findings = append(findings, f)
+			}
+		}
+	}
+
+	switch l.format {
+	case FormatSARIF:
+		err = WriteSARIF(l.out, findings, l.version)
+	default:
+		err = WriteText(l.out, findings)
+	}
+	if err != nil {
+		return err
+	}
+
+	problems := 0
+	for _, f := range findings {
+		if f.Level != LevelNote {
+			problems++
+		}
+	}
+	if problems > 0 {
+		return errors.Errorf("found %d problem(s) in the Logstash config", problems)
+	}
+
+	return nil
+}
+
+// ReadPipelines reads the Logstash config files grouped by pipeline. Each
+// of the paths, either a Logstash config file or a directory containing
+// Logstash config files, is a pipeline of its own, as are the pipelines of
+// the pipelines.yml given by pipelinesFile, if not empty.
+func ReadPipelines(paths []string, pipelinesFile, pipelineBase string) ([][]File, error) {
+	var pipelines [][]File
+
+	if pipelinesFile != "" {
+		archive, err := pipeline.New(pipelinesFile, pipelineBase)
+		if err != nil {
+			return nil, err
+		}
+		for _, pattern := range archive.ConfigPatterns() {
+			filenames, err := doublestar.Glob(pattern)
+			if err != nil {
+				return nil, err
+			}
+			files, err := readFiles(filenames)
+			if err != nil {
+				return nil, err
+			}
+			pipelines = append(pipelines, files)
+		}
+	}
+
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		filenames := []string{path}
+		if fi.IsDir() {
+			filenames, err = filepath.Glob(filepath.Join(path, "*"))
+			if err != nil {
+				return nil, err
+			}
+		}
+		files, err := readFiles(filenames)
+		if err != nil {
+			return nil, err
+		}
+		pipelines = append(pipelines, files)
+	}
+
+	return pipelines, nil
+}
+
+func readFiles(filenames []string) ([]File, error) {
+	sort.Strings(filenames)
+
+	files := make([]File, 0, len(filenames))
+	for _, filename := range filenames {
+		fi, err := os.Stat(filename)
+		if err != nil {
+			return nil, err
+		}
+		if fi.IsDir() {
+			continue
+		}
+		body, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read Logstash config %q", filename)
+		}
+		files = append(files, File{Name: filename, Body: body})
+	}
+	return files, nil
+}
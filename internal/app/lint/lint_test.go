@@ -0,0 +1,205 @@
+package lint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/app/lint"
+)
+
+func TestCheck(t *testing.T) {
+	type finding struct {
+		rule string
+		line int
+	}
+
+	cases := []struct {
+		name  string
+		files []string
+
+		want []finding
+	}{
+		{
+			name: "no problems",
+			files: []string{`input { stdin { id => in } }
+filter {
+  grok { id => parse match => { "message" => "%{WORD:verb} (?<user>[a-z]+)" } }
+  if [verb] == "GET" and [user] {
+    mutate { id => tag add_tag => [ "get" ] }
+  }
+  if "get" in [tags] {
+    mutate { id => untag remove_tag => [ "get", "_grokparsefailure" ] }
+  }
+}
+output { stdout { id => out } }
+`},
+		},
+		{
+			name:  "syntax error",
+			files: []string{"filter {\n  mutate { id => x }\n}\n"},
+
+			want: []finding{{"syntax", 2}},
+		},
+		{
+			name: "missing and duplicate ids across files",
+			files: []string{
+				"filter {\n  mutate { id => one }\n  mutate { }\n}\n",
+				"filter {\n  mutate { id => one }\n}\n",
+			},
+
+			want: []finding{{"missing-id", 3}, {"duplicate-id", 2}},
+		},
+		{
+			name: "unreachable branches",
+			files: []string{`filter {
+  if [message] == "a" {
+    mutate { id => one }
+  } else if [message] == "a" {
+    mutate { id => two }
+  }
+  if "true" {
+    mutate { id => three }
+  } else if [message] {
+    mutate { id => four }
+  } else {
+    mutate { id => five }
+  }
+}
+`},
+
+			want: []finding{{"unreachable-branch", 4}, {"unreachable-branch", 9}, {"unreachable-branch", 11}},
+		},
+		{
+			name: "undefined fields",
+			files: []string{`filter {
+  mutate { id => rename rename => { "message" => "[log][text]" } add_field => { "[a][b]" => "c" } }
+  if [log][text] or [a] or [a][b][c] or [missing] {
+    mutate { id => one }
+  }
+  if [missing] or [other] {
+    mutate { id => two }
+  }
+}
+`},
+
+			want: []finding{{"undefined-field", 3}, {"undefined-field", 6}},
+		},
+		{
+			name: "undefined fields with dynamic input",
+			files: []string{`input { stdin { id => in codec => json } }
+filter {
+  if [missing] {
+    mutate { id => one remove_tag => [ "unknown" ] }
+  }
+}
+`},
+		},
+		{
+			name: "deprecated options",
+			files: []string{`filter {
+  translate { id => translate field => "a" destination => "b" dictionary => { "x" => "y" } }
+}
+`},
+
+			want: []finding{{"deprecated-option", 2}, {"deprecated-option", 2}},
+		},
+		{
+			name: "unbalanced tags",
+			files: []string{`filter {
+  mutate { id => one remove_tag => [ "never" ] }
+  mutate { id => two add_tag => [ "same" ] remove_tag => [ "same" ] }
+  mutate { id => three remove_tag => [ "%{dynamic}" ] }
+}
+`},
+
+			want: []finding{{"unbalanced-tag", 2}, {"unbalanced-tag", 3}},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			var files []lint.File
+			for i, body := range test.files {
+				files = append(files, lint.File{Name: string(rune('a'+i)) + ".conf", Body: []byte(body)})
+			}
+
+			var got []finding
+			for _, f := range lint.Check(files) {
+				got = append(got, finding{f.Rule, f.Line})
+			}
+			is.Equal(got, test.want)
+		})
+	}
+}
+
+var findings = []lint.Finding{
+	{Rule: "syntax", Level: lint.LevelError, File: "a.conf", Message: "not a valid Logstash config"},
+	{Rule: "missing-id", Level: lint.LevelError, File: "b.conf", Line: 3, Column: 5, Message: "mutate filter plugin has no id"},
+}
+
+func TestWriteText(t *testing.T) {
+	is := is.New(t)
+
+	var buf bytes.Buffer
+	err := lint.WriteText(&buf, findings)
+	is.NoErr(err)
+	is.Equal(buf.String(), `a.conf: error: not a valid Logstash config [syntax]
+b.conf:3:5: error: mutate filter plugin has no id [missing-id]
+`)
+}
+
+func TestWriteSARIF(t *testing.T) {
+	is := is.New(t)
+
+	var buf bytes.Buffer
+	err := lint.WriteSARIF(&buf, findings, "2.0.0")
+	is.NoErr(err)
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Version string `json:"version"`
+					Rules   []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region *struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	err = json.Unmarshal(buf.Bytes(), &log)
+	is.NoErr(err)
+
+	is.Equal(log.Version, "2.1.0")
+	is.Equal(len(log.Runs), 1)
+	run := log.Runs[0]
+	is.Equal(run.Tool.Driver.Version, "2.0.0")
+	is.Equal(len(run.Tool.Driver.Rules), len(lint.Rules))
+	is.Equal(len(run.Results), 2)
+	is.Equal(run.Results[0].Locations[0].PhysicalLocation.Region, nil) // no region without position
+	is.Equal(run.Results[1].RuleID, "missing-id")
+	is.Equal(run.Results[1].Level, "error")
+	is.Equal(run.Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI, "b.conf")
+	is.Equal(run.Results[1].Locations[0].PhysicalLocation.Region.StartLine, 3)
+}
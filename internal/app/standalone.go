@@ -11,6 +11,7 @@ import (
 
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/app/standalone"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/testcase"
 )
 
 func makeStandaloneCmd() *cobra.Command {
@@ -21,8 +22,7 @@ func makeStandaloneCmd() *cobra.Command {
 		Args:  validateStandaloneArgs,
 	}
 
-	// TODO: This flag makes sense with daemon mode test command as well.
-	cmd.Flags().String("diff-command", "diff -u", "Set the command to run to compare two events. The command will receive the two files to compare as arguments.")
+	cmd.Flags().String("diff-command", "diff -u", "Set the command to run to compare two events. The command will receive the two files to compare as arguments. If empty, the events are compared with the builtin comparator.")
 	_ = viper.BindPFlag("diff-command", cmd.Flags().Lookup("diff-command"))
 
 	// TODO: Move default values to some sort of global lookup like defaultKeptEnvVars.
@@ -56,10 +56,39 @@ func makeStandaloneCmd() *cobra.Command {
 	cmd.Flags().Bool("quiet", false, "Omit test progress messages and event diffs.")
 	_ = viper.BindPFlag("quiet", cmd.Flags().Lookup("quiet"))
 
+	cmd.Flags().StringSlice("report", nil, "Write a report of the test results in the format <type>:<path> (e.g. junit:report.xml). Supported types: junit. May be given multiple times.")
+	_ = viper.BindPFlag("reports", cmd.Flags().Lookup("report"))
+
+	cmd.Flags().Bool("update", false, "Rewrite the expected events in the test case files of the failing tests with the actual events.")
+	_ = viper.BindPFlag("update", cmd.Flags().Lookup("update"))
+
+	cmd.Flags().String("output-format", "text", "Format of the test results printed to stdout, one of: text, json (one JSON object per line).")
+	_ = viper.BindPFlag("output-format", cmd.Flags().Lookup("output-format"))
+
+	cmd.Flags().StringSlice("testcase-include", testcase.DefaultIncludePatterns, "Only discover the test case files matching at least one of the patterns (e.g. **/*.json), relative to the test case directory. May be given multiple times.")
+	_ = viper.BindPFlag("testcase-include", cmd.Flags().Lookup("testcase-include"))
+
+	cmd.Flags().StringSlice("testcase-exclude", nil, "Skip the test case files and directories matching at least one of the patterns, relative to the test case directory. May be given multiple times.")
+	_ = viper.BindPFlag("testcase-exclude", cmd.Flags().Lookup("testcase-exclude"))
+
+	cmd.Flags().String("run", "", "Only run the test cases, whose description or test case file name matches the regular expression.")
+	_ = viper.BindPFlag("run", cmd.Flags().Lookup("run"))
+
+	cmd.Flags().StringSlice("tags", nil, "Only run the test cases with at least one of the given tags. May be given multiple times.")
+	_ = viper.BindPFlag("tags", cmd.Flags().Lookup("tags"))
+
+	cmd.Flags().StringSlice("skip-tags", nil, "Skip the test cases with at least one of the given tags. May be given multiple times.")
+	_ = viper.BindPFlag("skip-tags", cmd.Flags().Lookup("skip-tags"))
+
 	return cmd
 }
 
 func runStandalone(_ *cobra.Command, args []string) error {
+	filter, err := testcase.NewFilter(viper.GetString("run"), viper.GetStringSlice("tags"), viper.GetStringSlice("skip-tags"))
+	if err != nil {
+		return fmt.Errorf("--run: %s", err)
+	}
+
 	s := standalone.New(
 		viper.GetBool("quiet"),
 		viper.GetString("diff-command"),
@@ -72,6 +101,12 @@ func runStandalone(_ *cobra.Command, args []string) error {
 		args[1:],
 		viper.GetBool("sockets"),
 		viper.GetDuration("sockets-timeout"),
+		viper.GetStringSlice("reports"),
+		viper.GetString("output-format"),
+		viper.GetBool("update"),
+		viper.GetStringSlice("testcase-include"),
+		viper.GetStringSlice("testcase-exclude"),
+		filter,
 		viper.Get("logger").(logging.Logger),
 	)
 
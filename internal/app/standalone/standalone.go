@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
@@ -43,6 +42,12 @@ type Standalone struct {
 	configPaths           []string
 	unixSockets           bool
 	unixSocketCommTimeout time.Duration
+	reports               []string
+	outputFormat          string
+	update                bool
+	include               []string
+	exclude               []string
+	filter                testcase.Filter
 
 	log logging.Logger
 }
@@ -59,6 +64,12 @@ func New(
 	configPaths []string,
 	unixSockets bool,
 	unixSocketCommTimeout time.Duration,
+	reports []string,
+	outputFormat string,
+	update bool,
+	include []string,
+	exclude []string,
+	filter testcase.Filter,
 	log logging.Logger,
 ) Standalone {
 	return Standalone{
@@ -73,6 +84,12 @@ func New(
 		configPaths:           configPaths,
 		unixSockets:           unixSockets,
 		unixSocketCommTimeout: unixSocketCommTimeout,
+		reports:               reports,
+		outputFormat:          outputFormat,
+		update:                update,
+		include:               include,
+		exclude:               exclude,
+		filter:                filter,
 		log:                   log,
 	}
 }
@@ -83,9 +100,18 @@ func (s Standalone) Run() error {
 	// Set up observers
 	observers := make([]lfvobserver.Interface, 0)
 	liveObserver := observer.NewProperty(lfvobserver.TestExecutionStart{})
-	if !s.quiet {
-		observers = append(observers, lfvobserver.NewSummaryObserver(liveObserver))
+	if !s.quiet || s.outputFormat != lfvobserver.OutputFormatText {
+		outputObserver, err := lfvobserver.NewOutputObserver(liveObserver, s.outputFormat)
+		if err != nil {
+			return fmt.Errorf("Initialization error: %s", err)
+		}
+		observers = append(observers, outputObserver)
 	}
+	reportObservers, err := lfvobserver.NewReportObservers(liveObserver, s.reports)
+	if err != nil {
+		return fmt.Errorf("Initialization error: %s", err)
+	}
+	observers = append(observers, reportObservers...)
 	for _, obs := range observers {
 		if err := obs.Start(); err != nil {
 			return fmt.Errorf("Initialization error: %s", err)
@@ -97,10 +123,18 @@ func (s Standalone) Run() error {
 		return fmt.Errorf("Error parsing diff command %q: %s", s.diffCommand, err)
 	}
 
-	tests, err := testcase.DiscoverTests(s.testcasePath)
+	tests, err := testcase.DiscoverTests(s.testcasePath, s.include, s.exclude)
 	if err != nil {
 		return fmt.Errorf(err.Error())
 	}
+	tests, err = s.filter.Apply(tests)
+	if err != nil {
+		return err
+	}
+	if len(tests) == 0 {
+		s.log.Warning("No test cases selected")
+		return nil
+	}
 
 	allKeptEnvVars := append(defaultKeptEnvVars, s.keptEnvVars...)
 
@@ -131,7 +165,7 @@ func (s Standalone) Run() error {
 		if runtime.GOOS == "windows" {
 			return fmt.Errorf("Use of Unix domain sockets for communication with Logstash is not supported on Windows.")
 		}
-		fmt.Println("Use Unix domain sockets.")
+		s.progressf("Use Unix domain sockets.\n")
 		if status, err = s.runParallelTests(inv, tests, diffCmd, allKeptEnvVars, liveObserver); err != nil {
 			return fmt.Errorf(err.Error())
 		}
@@ -185,7 +219,7 @@ func (s Standalone) findExecutable(paths []string) (string, error) {
 func (s Standalone) runTests(inv *logstash.Invocation, tests []testcase.TestCaseSet, diffCommand []string, keptEnvVars []string, liveObserver observer.Property) (bool, error) {
 	ok := true
 	for _, t := range tests {
-		fmt.Printf("Running tests in %s...\n", filepath.Base(t.File))
+		s.progressf("Running tests in %s...\n", t.Name())
 		p, err := logstash.NewProcess(inv, t.Codec, t.InputFields, keptEnvVars)
 		if err != nil {
 			return false, err
@@ -218,6 +252,12 @@ func (s Standalone) runTests(inv *logstash.Invocation, tests []testcase.TestCase
 		if err != nil {
 			return false, err
 		}
+		if !currentOk && s.update {
+			if err = s.updateExpected(t, result.Events); err != nil {
+				return false, err
+			}
+			currentOk = true
+		}
 		if !currentOk {
 			ok = false
 		}
@@ -290,7 +330,13 @@ func (s Standalone) runParallelTests(inv *logstash.Invocation, tests []testcase.
 	for i, t := range tests {
 		currentOk, err := t.Compare(result.Events[i], diffCommand, liveProducer)
 		if err != nil {
-			userError("Testcase %s failed, continuing with the rest: %s", filepath.Base(t.File), err)
+			userError("Testcase %s failed, continuing with the rest: %s", t.Name(), err)
+		}
+		if err == nil && !currentOk && s.update {
+			if err = s.updateExpected(t, result.Events[i]); err != nil {
+				return false, err
+			}
+			currentOk = true
 		}
 		if !currentOk {
 			ok = false
@@ -300,6 +346,25 @@ func (s Standalone) runParallelTests(inv *logstash.Invocation, tests []testcase.
 	return ok, nil
 }
 
+// updateExpected rewrites the expected events in the test case file with
+// the actual events.
+func (s Standalone) updateExpected(t testcase.TestCaseSet, events []logstash.Event) error {
+	if err := t.UpdateExpected(events, nil); err != nil {
+		return err
+	}
+	s.progressf("Updated expected events in %s\n", t.Name())
+	return nil
+}
+
+// progressf prints a progress message to stdout, unless the results
+// are written in a machine-readable output format.
+func (s Standalone) progressf(format string, a ...interface{}) {
+	if s.outputFormat != lfvobserver.OutputFormatText {
+		return
+	}
+	fmt.Printf(format, a...)
+}
+
 // getLogstashOutputMessage examines the test result and prepares a
 // message describing the process's output, log output, or neither
 // (resulting in an empty string).
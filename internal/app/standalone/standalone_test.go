@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/testcase"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/testhelpers"
 )
 
@@ -123,7 +124,7 @@ func TestFindExecutable(t *testing.T) {
 			absInputs[i] = filepath.Join(tempdir, p)
 		}
 
-		standalone := New(false, "", "", nil, nil, "", nil, false, nil, false, 0, nilLogger{})
+		standalone := New(false, "", "", nil, nil, "", nil, false, nil, false, 0, nil, "text", false, nil, nil, testcase.Filter{}, nilLogger{})
 		result, err := standalone.findExecutable(absInputs)
 		if err == nil && c.errorRegexp != nil {
 			t.Errorf("Test %d: Expected failure, got success.", i)
@@ -0,0 +1,312 @@
+// Package coverage collects, which plugins and conditional branches of a
+// Logstash config are exercised by the test cases.
+//
+// The conditional branches of the filter sections are instrumented with a
+// no-op marker plugin (mutate filter with a well known ID), such that the
+// hits of the branches, like the hits of the filter plugins, can be taken
+// from the plugin statistics of Logstash. The hits of the inputs are taken
+// from the test case sets, the hits of the outputs from the events
+// received by the outputs. The hits of the conditional branches of the
+// input and output sections are derived from the plugins within.
+package coverage
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/breml/logstash-config/ast"
+)
+
+// MarkerPrefix is the prefix of the IDs of the marker plugins.
+const MarkerPrefix = "__lfv_coverage_"
+
+// Kind is the kind of a coverage point.
+type Kind string
+
+const (
+	KindPlugin Kind = "plugin"
+	KindBranch Kind = "branch"
+)
+
+type source int
+
+const (
+	// sourcePluginIn takes the hits from the events received by the plugin
+	// according to the plugin statistics of Logstash.
+	sourcePluginIn source = iota
+	// sourcePluginOut takes the hits from the events emitted by the plugin
+	// according to the plugin statistics of Logstash.
+	sourcePluginOut
+	// sourceInput takes the hits from the test case sets.
+	sourceInput
+	// sourceOutput takes the hits from the events received by the outputs.
+	sourceOutput
+	// sourceChildren derives the hits from the plugins within a branch.
+	sourceChildren
+)
+
+// Point is a plugin or a conditional branch of a Logstash config.
+type Point struct {
+	Section string `json:"section"`
+	Kind    Kind   `json:"kind"`
+	// Label describes the point, e.g. the name of the plugin or the
+	// condition of the branch.
+	Label string `json:"label"`
+	// ID is the ID of the plugin or of the marker plugin of the branch.
+	ID     string `json:"id,omitempty"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Hits   int64  `json:"hits"`
+	// Measured is false, if the hits of the point can not be determined,
+	// e.g. for a branch without plugins in an output section.
+	Measured bool `json:"measured"`
+
+	source   source
+	key      string
+	children []*Point
+}
+
+// Covered returns true, if the point has been hit at least once.
+func (p *Point) Covered() bool {
+	return p.Measured && p.Hits > 0
+}
+
+// File contains the coverage points of a Logstash config file.
+type File struct {
+	Name   string   `json:"name"`
+	Points []*Point `json:"points"`
+
+	source string
+}
+
+// Coverage collects the coverage points of the Logstash config files and
+// their hits. It is safe for concurrent use.
+type Coverage struct {
+	mutex   *sync.Mutex
+	files   []*File
+	markers int
+}
+
+// New creates an empty Coverage.
+func New() *Coverage {
+	return &Coverage{
+		mutex: &sync.Mutex{},
+	}
+}
+
+// Instrument adds the plugins and the conditional branches of config as
+// coverage points and instruments the conditional branches of the filter
+// sections with marker plugins. source is the body of the original config
+// file, which is used for the HTML report. If config has not been parsed
+// from source (e.g. because it has been rewritten), position maps the
+// positions in config to the positions in source, otherwise it may be nil.
+// outputName returns the name, under which the events passing the output
+// with the given ID are reported (see AddOutput).
+func (c *Coverage) Instrument(name string, source []byte, config *ast.Config, position func(pos ast.Pos) ast.Pos, outputName func(id string) string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	i := instrumenter{
+		coverage:   c,
+		file:       &File{Name: name, source: string(source)},
+		position:   position,
+		outputName: outputName,
+	}
+
+	for _, sections := range [][]ast.PluginSection{config.Input, config.Filter, config.Output} {
+		for j := range sections {
+			i.section = sections[j].PluginType
+			sections[j].BranchOrPlugins, _ = i.block(sections[j].BranchOrPlugins)
+		}
+	}
+
+	c.files = append(c.files, i.file)
+}
+
+type instrumenter struct {
+	coverage   *Coverage
+	file       *File
+	section    ast.PluginType
+	position   func(pos ast.Pos) ast.Pos
+	outputName func(id string) string
+}
+
+// pos returns the position in the source of the config file.
+func (i *instrumenter) pos(pos ast.Pos) ast.Pos {
+	if i.position == nil {
+		return pos
+	}
+	return i.position(pos)
+}
+
+// block adds the coverage points for the plugins and branches of a block
+// and returns the instrumented block together with all the plugin points
+// within.
+func (i *instrumenter) block(bops []ast.BranchOrPlugin) ([]ast.BranchOrPlugin, []*Point) {
+	var plugins []*Point
+	for j, bop := range bops {
+		switch node := bop.(type) {
+		case ast.Plugin:
+			plugins = append(plugins, i.plugin(node))
+		case ast.Branch:
+			var points []*Point
+			bops[j], points = i.branch(node)
+			plugins = append(plugins, points...)
+		}
+	}
+	return bops, plugins
+}
+
+func (i *instrumenter) plugin(plugin ast.Plugin) *Point {
+	id, err := plugin.ID()
+	if err != nil {
+		id = ""
+	}
+
+	pos := i.pos(plugin.Pos())
+	p := &Point{
+		Section:  i.section.String(),
+		Kind:     KindPlugin,
+		Label:    plugin.Name(),
+		ID:       id,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Measured: id != "",
+		key:      id,
+	}
+
+	// Plugins of type pipeline are not replaced by LFV, therefore the
+	// plugin statistics are available for them.
+	switch {
+	case i.section == ast.Input && plugin.Name() == "pipeline":
+		p.source = sourcePluginOut
+	case i.section == ast.Input:
+		p.source = sourceInput
+	case i.section == ast.Output && plugin.Name() != "pipeline":
+		p.source = sourceOutput
+		p.key = i.outputName(id)
+	default:
+		p.source = sourcePluginIn
+	}
+
+	i.file.Points = append(i.file.Points, p)
+	return p
+}
+
+func (i *instrumenter) branch(branch ast.Branch) (ast.Branch, []*Point) {
+	var plugins []*Point
+	block := func(label string, pos ast.Pos, bops []ast.BranchOrPlugin) []ast.BranchOrPlugin {
+		pos = i.pos(pos)
+		p := &Point{
+			Section:  i.section.String(),
+			Kind:     KindBranch,
+			Label:    label,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Measured: true,
+		}
+		i.file.Points = append(i.file.Points, p)
+
+		bops, points := i.block(bops)
+		plugins = append(plugins, points...)
+
+		if i.section != ast.Filter {
+			p.source = sourceChildren
+			p.children = points
+			p.Measured = len(points) > 0
+			return bops
+		}
+
+		p.source = sourcePluginIn
+		p.ID = i.coverage.nextMarker()
+		p.key = p.ID
+		marker := ast.NewPlugin("mutate", ast.NewStringAttribute("id", p.ID, ast.DoubleQuoted))
+		return append([]ast.BranchOrPlugin{marker}, bops...)
+	}
+
+	branch.IfBlock.Block = block("if "+branch.IfBlock.Condition.String(), branch.IfBlock.Pos(), branch.IfBlock.Block)
+	for j := range branch.ElseIfBlock {
+		branch.ElseIfBlock[j].Block = block("else if "+branch.ElseIfBlock[j].Condition.String(), branch.ElseIfBlock[j].Pos(), branch.ElseIfBlock[j].Block)
+	}
+
+	switch {
+	case branch.ElseBlock.Pos().Line > 0 || len(branch.ElseBlock.Block) > 0:
+		branch.ElseBlock.Block = block("else", branch.ElseBlock.Pos(), branch.ElseBlock.Block)
+	case i.section == ast.Filter:
+		// The implicit else branch is reported at the position of the if.
+		branch.ElseBlock.Block = block("else (implicit)", branch.IfBlock.Pos(), nil)
+	}
+
+	return branch, plugins
+}
+
+func (c *Coverage) nextMarker() string {
+	c.markers++
+	return fmt.Sprintf("%s%d", MarkerPrefix, c.markers)
+}
+
+// AddPluginStats adds the events received (eventsIn) and emitted
+// (eventsOut) by the plugin with the given ID according to the plugin
+// statistics of Logstash.
+func (c *Coverage) AddPluginStats(id string, eventsIn int64, eventsOut int64) {
+	c.add(func(p *Point) {
+		switch {
+		case p.key != id:
+		case p.source == sourcePluginIn:
+			p.Hits += eventsIn
+		case p.source == sourcePluginOut:
+			p.Hits += eventsOut
+		}
+	})
+}
+
+// AddInput adds the events fed to the input plugin with the given ID by
+// the test case sets.
+func (c *Coverage) AddInput(id string, events int64) {
+	c.add(func(p *Point) {
+		if p.source == sourceInput && p.key == id {
+			p.Hits += events
+		}
+	})
+}
+
+// AddOutput adds the events received by the output with the given name.
+func (c *Coverage) AddOutput(name string, events int64) {
+	c.add(func(p *Point) {
+		if p.source == sourceOutput && p.key == name {
+			p.Hits += events
+		}
+	})
+}
+
+func (c *Coverage) add(f func(p *Point)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, file := range c.files {
+		for _, p := range file.Points {
+			f(p)
+		}
+	}
+}
+
+// Files returns the coverage points of the Logstash config files.
+func (c *Coverage) Files() []*File {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, file := range c.files {
+		for _, p := range file.Points {
+			if p.source != sourceChildren {
+				continue
+			}
+			p.Hits = 0
+			for _, child := range p.children {
+				if child.Measured {
+					p.Hits += child.Hits
+				}
+			}
+		}
+	}
+	return c.files
+}
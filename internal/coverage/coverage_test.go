@@ -0,0 +1,235 @@
+package coverage_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	config "github.com/breml/logstash-config"
+	"github.com/breml/logstash-config/ast"
+	"github.com/matryer/is"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/coverage"
+)
+
+const testConfig = `input {
+  stdin { id => in }
+}
+filter {
+  if [type] == "a" {
+    grok { id => parse }
+  } else if [type] == "b" {
+    drop { id => drop }
+  }
+  mutate { id => always }
+}
+output {
+  if [type] == "a" {
+    stdout { id => "out.a" }
+  } else {
+  }
+}
+`
+
+func instrument(t *testing.T) (*coverage.Coverage, ast.Config) {
+	t.Helper()
+	is := is.New(t)
+
+	icfg, err := config.Parse("test.conf", []byte(testConfig))
+	is.NoErr(err)
+	cfg := icfg.(ast.Config)
+
+	c := coverage.New()
+	c.Instrument("test.conf", []byte(testConfig), &cfg, nil, strings.ToUpper)
+
+	return c, cfg
+}
+
+func TestInstrument(t *testing.T) {
+	is := is.New(t)
+
+	c, cfg := instrument(t)
+
+	instrumented := cfg.String()
+	is.True(strings.Contains(instrumented, `if [type] == "a" {
+    mutate {
+      id => "__lfv_coverage_1"
+    }`)) // marker in if branch
+	is.True(strings.Contains(instrumented, `else if [type] == "b" {
+    mutate {
+      id => "__lfv_coverage_2"
+    }`)) // marker in else if branch
+	is.True(strings.Contains(instrumented, `else {
+    mutate {
+      id => "__lfv_coverage_3"
+    }`)) // implicit else branch with marker
+	is.Equal(strings.Count(instrumented, "__lfv_coverage_"), 3) // no markers in the output section
+
+	type point struct {
+		section  string
+		kind     coverage.Kind
+		label    string
+		line     int
+		measured bool
+	}
+	var points []point
+	for _, p := range c.Files()[0].Points {
+		points = append(points, point{p.Section, p.Kind, p.Label, p.Line, p.Measured})
+	}
+	is.Equal(points, []point{
+		{"input", coverage.KindPlugin, "stdin", 2, true},
+		{"filter", coverage.KindBranch, `if [type] == "a"`, 5, true},
+		{"filter", coverage.KindPlugin, "grok", 6, true},
+		{"filter", coverage.KindBranch, `else if [type] == "b"`, 7, true},
+		{"filter", coverage.KindPlugin, "drop", 8, true},
+		{"filter", coverage.KindBranch, "else (implicit)", 5, true},
+		{"filter", coverage.KindPlugin, "mutate", 10, true},
+		{"output", coverage.KindBranch, `if [type] == "a"`, 13, true},
+		{"output", coverage.KindPlugin, "stdout", 14, true},
+		{"output", coverage.KindBranch, "else", 15, false}, // empty output branch can not be measured
+	})
+}
+
+func TestHits(t *testing.T) {
+	is := is.New(t)
+
+	c, _ := instrument(t)
+
+	c.AddInput("in", 3)
+	c.AddInput("other", 1)
+	c.AddPluginStats("parse", 2, 2)
+	c.AddPluginStats("__lfv_coverage_1", 2, 2)
+	c.AddPluginStats("always", 3, 3)
+	c.AddOutput("OUT.A", 2) // name as returned by outputName
+	c.AddOutput("out.a", 5)
+
+	hits := map[string]int64{}
+	for _, p := range c.Files()[0].Points {
+		hits[p.Label] += p.Hits
+	}
+	is.Equal(hits, map[string]int64{
+		"stdin":                 3,
+		`if [type] == "a"`:      4, // filter marker and output branch derived from stdout
+		"grok":                  2,
+		`else if [type] == "b"`: 0,
+		"drop":                  0,
+		"else (implicit)":       0,
+		"mutate":                3,
+		"stdout":                2,
+		"else":                  0,
+	})
+}
+
+func TestWriteText(t *testing.T) {
+	is := is.New(t)
+
+	c, _ := instrument(t)
+	c.AddInput("in", 3)
+	c.AddPluginStats("parse", 2, 2)
+	c.AddPluginStats("__lfv_coverage_1", 2, 2)
+	c.AddPluginStats("always", 3, 3)
+	c.AddOutput("OUT.A", 2)
+
+	var buf bytes.Buffer
+	err := c.WriteText(&buf)
+	is.NoErr(err)
+	is.Equal(buf.String(), `Coverage:
+  FILE       PLUGINS      BRANCHES
+  test.conf  4/5 (80.0%)  2/4 (50.0%)
+  total      4/5 (80.0%)  2/4 (50.0%)
+
+Not covered:
+  test.conf:7: filter else if [type] == "b"
+  test.conf:8: filter drop (id: drop)
+  test.conf:5: filter else (implicit)
+`)
+}
+
+func TestWriteJSON(t *testing.T) {
+	is := is.New(t)
+
+	c, _ := instrument(t)
+	c.AddPluginStats("parse", 2, 2)
+
+	var buf bytes.Buffer
+	err := c.WriteJSON(&buf)
+	is.NoErr(err)
+
+	var report struct {
+		Files []struct {
+			Name    string           `json:"name"`
+			Points  []coverage.Point `json:"points"`
+			Summary coverage.Summary `json:"summary"`
+		} `json:"files"`
+	}
+	err = json.Unmarshal(buf.Bytes(), &report)
+	is.NoErr(err)
+	is.Equal(len(report.Files), 1)
+	is.Equal(report.Files[0].Name, "test.conf")
+	is.Equal(len(report.Files[0].Points), 10)
+	is.Equal(report.Files[0].Summary, coverage.Summary{Plugins: 5, PluginsCovered: 1, Branches: 4, BranchesCovered: 0})
+}
+
+func TestWriteHTML(t *testing.T) {
+	is := is.New(t)
+
+	c, _ := instrument(t)
+	c.AddPluginStats("parse", 2, 2)
+
+	var buf bytes.Buffer
+	err := c.WriteHTML(&buf)
+	is.NoErr(err)
+
+	html := buf.String()
+	is.True(strings.Contains(html, `<span class="line covered"><span class="lineno">6</span>    grok { id =&gt; parse }<span class="hits">grok: 2</span></span>`)) // covered plugin
+	is.True(strings.Contains(html, `<span class="line uncovered"><span class="lineno">8</span>`))                                                                  // uncovered plugin
+	is.True(strings.Contains(html, `<span class="line"><span class="lineno">1</span>input {</span>`))                                                              // line without points
+}
+
+func TestParseReports(t *testing.T) {
+	cases := []struct {
+		name    string
+		reports []string
+
+		want    []coverage.Report
+		wantErr bool
+	}{
+		{
+			name:    "all formats",
+			reports: []string{"text", "text:coverage.txt", "json:coverage.json", "html:coverage.html"},
+
+			want: []coverage.Report{
+				{Format: "text"},
+				{Format: "text", Path: "coverage.txt"},
+				{Format: "json", Path: "coverage.json"},
+				{Format: "html", Path: "coverage.html"},
+			},
+		},
+		{
+			name:    "html without path",
+			reports: []string{"html"},
+
+			wantErr: true,
+		},
+		{
+			name:    "unsupported format",
+			reports: []string{"xml:coverage.xml"},
+
+			wantErr: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			reports, err := coverage.ParseReports(test.reports)
+			is.Equal(err != nil, test.wantErr) // error
+			if test.wantErr {
+				return
+			}
+			is.Equal(reports, test.want)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package coverage
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+const htmlTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Logstash config coverage</title>
+<style>
+body { font-family: sans-serif; }
+table.summary td, table.summary th { padding: 2px 12px; text-align: left; }
+pre { line-height: 1.3; }
+.line { display: block; }
+.lineno { display: inline-block; width: 4em; color: #999; text-align: right; margin-right: 1em; user-select: none; }
+.covered { background-color: #d4f7d4; }
+.uncovered { background-color: #f7d4d4; }
+.hits { color: #666; margin-left: 2em; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>Logstash config coverage</h1>
+<table class="summary">
+<tr><th>File</th><th>Plugins</th><th>Branches</th></tr>
+{{- range .Files }}
+<tr><td><a href="#{{ .Anchor }}">{{ .Name }}</a></td><td>{{ .Plugins }}</td><td>{{ .Branches }}</td></tr>
+{{- end }}
+</table>
+{{- range .Files }}
+<h2 id="{{ .Anchor }}">{{ .Name }}</h2>
+<pre>
+{{- range .Lines }}<span class="line{{ if .Class }} {{ .Class }}{{ end }}"><span class="lineno">{{ .Number }}</span>{{ .Text }}{{ if .Hits }}<span class="hits">{{ .Hits }}</span>{{ end }}</span>{{ end -}}
+</pre>
+{{- end }}
+</body>
+</html>
+`
+
+type htmlLine struct {
+	Number int
+	Text   string
+	Class  string
+	Hits   string
+}
+
+type htmlFile struct {
+	Name     string
+	Anchor   string
+	Plugins  string
+	Branches string
+	Lines    []htmlLine
+}
+
+// WriteHTML writes the Logstash config files as HTML, the lines with
+// covered points are highlighted in green, the lines with points, which
+// are not covered, in red.
+func (c *Coverage) WriteHTML(w io.Writer) error {
+	tmpl, err := template.New("coverage").Parse(htmlTemplate)
+	if err != nil {
+		return err
+	}
+
+	files := c.Files()
+	data := struct {
+		Files []htmlFile
+	}{
+		Files: make([]htmlFile, 0, len(files)),
+	}
+
+	for i, file := range files {
+		s := file.Summarize()
+		f := htmlFile{
+			Name:     file.Name,
+			Anchor:   fmt.Sprintf("file%d", i),
+			Plugins:  percentage(s.PluginsCovered, s.Plugins),
+			Branches: percentage(s.BranchesCovered, s.Branches),
+		}
+
+		points := map[int][]*Point{}
+		for _, p := range file.Points {
+			if p.Measured {
+				points[p.Line] = append(points[p.Line], p)
+			}
+		}
+
+		for j, text := range strings.Split(strings.TrimSuffix(file.source, "\n"), "\n") {
+			line := htmlLine{
+				Number: j + 1,
+				Text:   text,
+			}
+			hits := make([]string, 0, len(points[line.Number]))
+			for _, p := range points[line.Number] {
+				switch {
+				case !p.Covered():
+					line.Class = "uncovered"
+				case line.Class == "":
+					line.Class = "covered"
+				}
+				hits = append(hits, fmt.Sprintf("%s: %d", p.Label, p.Hits))
+			}
+			line.Hits = strings.Join(hits, ", ")
+			f.Lines = append(f.Lines, line)
+		}
+
+		data.Files = append(data.Files, f)
+	}
+
+	return tmpl.Execute(w, data)
+}
@@ -0,0 +1,192 @@
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// Report formats supported by ParseReports.
+const (
+	ReportText = "text"
+	ReportJSON = "json"
+	ReportHTML = "html"
+)
+
+// Report is the definition of a coverage report.
+type Report struct {
+	Format string
+	// Path of the report, if empty, the report is written to stdout.
+	Path string
+}
+
+// ParseReports parses the report definitions of the form
+// <format>[:<path>], e.g. html:coverage.html. Only text reports may omit
+// the path, they are written to stdout.
+func ParseReports(reports []string) ([]Report, error) {
+	parsed := make([]Report, 0, len(reports))
+	for _, report := range reports {
+		format, path, _ := strings.Cut(report, ":")
+		switch format {
+		case ReportText:
+		case ReportJSON, ReportHTML:
+			if path == "" {
+				return nil, fmt.Errorf("invalid coverage report %q, %s reports require a path (%s:<path>)", report, format, format)
+			}
+		default:
+			return nil, fmt.Errorf("invalid coverage report %q, expected format <format>[:<path>], supported formats: %s, %s, %s", report, ReportText, ReportJSON, ReportHTML)
+		}
+		parsed = append(parsed, Report{Format: format, Path: path})
+	}
+	return parsed, nil
+}
+
+// WriteReports writes the coverage reports.
+func (c *Coverage) WriteReports(reports []Report) error {
+	for _, report := range reports {
+		err := c.writeReport(report)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Coverage) writeReport(report Report) (err error) {
+	var w io.Writer = os.Stdout
+	if report.Path != "" {
+		f, err := os.Create(report.Path)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			closeErr := f.Close()
+			if err == nil {
+				err = closeErr
+			}
+		}()
+		w = f
+	}
+
+	switch report.Format {
+	case ReportJSON:
+		return c.WriteJSON(w)
+	case ReportHTML:
+		return c.WriteHTML(w)
+	default:
+		return c.WriteText(w)
+	}
+}
+
+// Summary contains the number of the coverage points and how many of them
+// are covered. Points, which can not be measured, are not counted.
+type Summary struct {
+	Plugins         int `json:"plugins"`
+	PluginsCovered  int `json:"plugins_covered"`
+	Branches        int `json:"branches"`
+	BranchesCovered int `json:"branches_covered"`
+}
+
+func (s *Summary) add(p *Point) {
+	if !p.Measured {
+		return
+	}
+	switch p.Kind {
+	case KindPlugin:
+		s.Plugins++
+		if p.Covered() {
+			s.PluginsCovered++
+		}
+	case KindBranch:
+		s.Branches++
+		if p.Covered() {
+			s.BranchesCovered++
+		}
+	}
+}
+
+// Summarize returns the summary of the coverage points of the file.
+func (f *File) Summarize() Summary {
+	var s Summary
+	for _, p := range f.Points {
+		s.add(p)
+	}
+	return s
+}
+
+func percentage(covered, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d (%.1f%%)", covered, total, float64(covered)*100/float64(total))
+}
+
+// WriteText writes a summary of the coverage per file followed by the
+// uncovered points.
+func (c *Coverage) WriteText(out io.Writer) error {
+	files := c.Files()
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Coverage:\n")
+	fmt.Fprintf(w, "  FILE\tPLUGINS\tBRANCHES\n")
+	var total Summary
+	for _, file := range files {
+		s := file.Summarize()
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", file.Name, percentage(s.PluginsCovered, s.Plugins), percentage(s.BranchesCovered, s.Branches))
+		total.Plugins += s.Plugins
+		total.PluginsCovered += s.PluginsCovered
+		total.Branches += s.Branches
+		total.BranchesCovered += s.BranchesCovered
+	}
+	fmt.Fprintf(w, "  total\t%s\t%s\n", percentage(total.PluginsCovered, total.Plugins), percentage(total.BranchesCovered, total.Branches))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	uncovered := false
+	for _, file := range files {
+		for _, p := range file.Points {
+			if !p.Measured || p.Covered() {
+				continue
+			}
+			if !uncovered {
+				fmt.Fprintf(out, "\nNot covered:\n")
+				uncovered = true
+			}
+			fmt.Fprintf(out, "  %s:%d: %s\n", file.Name, p.Line, p.describe())
+		}
+	}
+	return nil
+}
+
+func (p *Point) describe() string {
+	if p.Kind == KindPlugin {
+		return fmt.Sprintf("%s %s (id: %s)", p.Section, p.Label, p.ID)
+	}
+	return fmt.Sprintf("%s %s", p.Section, p.Label)
+}
+
+// WriteJSON writes the coverage points with their hits as JSON.
+func (c *Coverage) WriteJSON(w io.Writer) error {
+	type file struct {
+		*File
+		Summary Summary `json:"summary"`
+	}
+
+	files := c.Files()
+	report := struct {
+		Files []file `json:"files"`
+	}{
+		Files: make([]file, 0, len(files)),
+	}
+	for _, f := range files {
+		report.Files = append(report.Files, file{File: f, Summary: f.Summarize()})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
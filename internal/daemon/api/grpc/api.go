@@ -1,3 +1,8 @@
 package grpc
 
 //go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative api.proto
+
+// MaxMessageSize is the maximum size of the messages exchanged between the
+// daemon and the client. A batch with all the test case sets of a run can
+// easily exceed the default limit of 4 MB.
+const MaxMessageSize = 256 << 20
@@ -18,8 +18,17 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ControlClient interface {
 	Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error)
+	// Status returns information about the daemon, its Logstash instances and
+	// the active test sessions.
+	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
 	SetupTest(ctx context.Context, in *SetupTestRequest, opts ...grpc.CallOption) (*SetupTestResponse, error)
 	ExecuteTest(ctx context.Context, in *ExecuteTestRequest, opts ...grpc.CallOption) (*ExecuteTestResponse, error)
+	// ExecuteTestStream executes a test case set like ExecuteTest, but sends
+	// each event and the relevant Logstash log lines as soon as they arrive.
+	ExecuteTestStream(ctx context.Context, in *ExecuteTestRequest, opts ...grpc.CallOption) (Control_ExecuteTestStreamClient, error)
+	// ExecuteTestBatch executes multiple test case sets with a single reload
+	// of the Logstash config.
+	ExecuteTestBatch(ctx context.Context, in *ExecuteTestBatchRequest, opts ...grpc.CallOption) (*ExecuteTestBatchResponse, error)
 	TeardownTest(ctx context.Context, in *TeardownTestRequest, opts ...grpc.CallOption) (*TeardownTestResponse, error)
 }
 
@@ -40,6 +49,15 @@ func (c *controlClient) Shutdown(ctx context.Context, in *ShutdownRequest, opts
 	return out, nil
 }
 
+func (c *controlClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, "/grpc.Control/Status", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controlClient) SetupTest(ctx context.Context, in *SetupTestRequest, opts ...grpc.CallOption) (*SetupTestResponse, error) {
 	out := new(SetupTestResponse)
 	err := c.cc.Invoke(ctx, "/grpc.Control/SetupTest", in, out, opts...)
@@ -58,6 +76,47 @@ func (c *controlClient) ExecuteTest(ctx context.Context, in *ExecuteTestRequest,
 	return out, nil
 }
 
+func (c *controlClient) ExecuteTestStream(ctx context.Context, in *ExecuteTestRequest, opts ...grpc.CallOption) (Control_ExecuteTestStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[0], "/grpc.Control/ExecuteTestStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlExecuteTestStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Control_ExecuteTestStreamClient interface {
+	Recv() (*ExecuteTestStreamResponse, error)
+	grpc.ClientStream
+}
+
+type controlExecuteTestStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlExecuteTestStreamClient) Recv() (*ExecuteTestStreamResponse, error) {
+	m := new(ExecuteTestStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlClient) ExecuteTestBatch(ctx context.Context, in *ExecuteTestBatchRequest, opts ...grpc.CallOption) (*ExecuteTestBatchResponse, error) {
+	out := new(ExecuteTestBatchResponse)
+	err := c.cc.Invoke(ctx, "/grpc.Control/ExecuteTestBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controlClient) TeardownTest(ctx context.Context, in *TeardownTestRequest, opts ...grpc.CallOption) (*TeardownTestResponse, error) {
 	out := new(TeardownTestResponse)
 	err := c.cc.Invoke(ctx, "/grpc.Control/TeardownTest", in, out, opts...)
@@ -72,8 +131,17 @@ func (c *controlClient) TeardownTest(ctx context.Context, in *TeardownTestReques
 // for forward compatibility
 type ControlServer interface {
 	Shutdown(context.Context, *ShutdownRequest) (*ShutdownResponse, error)
+	// Status returns information about the daemon, its Logstash instances and
+	// the active test sessions.
+	Status(context.Context, *StatusRequest) (*StatusResponse, error)
 	SetupTest(context.Context, *SetupTestRequest) (*SetupTestResponse, error)
 	ExecuteTest(context.Context, *ExecuteTestRequest) (*ExecuteTestResponse, error)
+	// ExecuteTestStream executes a test case set like ExecuteTest, but sends
+	// each event and the relevant Logstash log lines as soon as they arrive.
+	ExecuteTestStream(*ExecuteTestRequest, Control_ExecuteTestStreamServer) error
+	// ExecuteTestBatch executes multiple test case sets with a single reload
+	// of the Logstash config.
+	ExecuteTestBatch(context.Context, *ExecuteTestBatchRequest) (*ExecuteTestBatchResponse, error)
 	TeardownTest(context.Context, *TeardownTestRequest) (*TeardownTestResponse, error)
 	mustEmbedUnimplementedControlServer()
 }
@@ -85,12 +153,21 @@ type UnimplementedControlServer struct {
 func (UnimplementedControlServer) Shutdown(context.Context, *ShutdownRequest) (*ShutdownResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Shutdown not implemented")
 }
+func (UnimplementedControlServer) Status(context.Context, *StatusRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
 func (UnimplementedControlServer) SetupTest(context.Context, *SetupTestRequest) (*SetupTestResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetupTest not implemented")
 }
 func (UnimplementedControlServer) ExecuteTest(context.Context, *ExecuteTestRequest) (*ExecuteTestResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExecuteTest not implemented")
 }
+func (UnimplementedControlServer) ExecuteTestStream(*ExecuteTestRequest, Control_ExecuteTestStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExecuteTestStream not implemented")
+}
+func (UnimplementedControlServer) ExecuteTestBatch(context.Context, *ExecuteTestBatchRequest) (*ExecuteTestBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteTestBatch not implemented")
+}
 func (UnimplementedControlServer) TeardownTest(context.Context, *TeardownTestRequest) (*TeardownTestResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TeardownTest not implemented")
 }
@@ -125,6 +202,24 @@ func _Control_Shutdown_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Control_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.Control/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).Status(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Control_SetupTest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SetupTestRequest)
 	if err := dec(in); err != nil {
@@ -161,6 +256,45 @@ func _Control_ExecuteTest_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Control_ExecuteTestStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecuteTestRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServer).ExecuteTestStream(m, &controlExecuteTestStreamServer{stream})
+}
+
+type Control_ExecuteTestStreamServer interface {
+	Send(*ExecuteTestStreamResponse) error
+	grpc.ServerStream
+}
+
+type controlExecuteTestStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlExecuteTestStreamServer) Send(m *ExecuteTestStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Control_ExecuteTestBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteTestBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).ExecuteTestBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.Control/ExecuteTestBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).ExecuteTestBatch(ctx, req.(*ExecuteTestBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Control_TeardownTest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(TeardownTestRequest)
 	if err := dec(in); err != nil {
@@ -187,6 +321,10 @@ var _Control_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Shutdown",
 			Handler:    _Control_Shutdown_Handler,
 		},
+		{
+			MethodName: "Status",
+			Handler:    _Control_Status_Handler,
+		},
 		{
 			MethodName: "SetupTest",
 			Handler:    _Control_SetupTest_Handler,
@@ -195,11 +333,21 @@ var _Control_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ExecuteTest",
 			Handler:    _Control_ExecuteTest_Handler,
 		},
+		{
+			MethodName: "ExecuteTestBatch",
+			Handler:    _Control_ExecuteTestBatch_Handler,
+		},
 		{
 			MethodName: "TeardownTest",
 			Handler:    _Control_TeardownTest_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExecuteTestStream",
+			Handler:       _Control_ExecuteTestStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "api.proto",
 }
@@ -5,8 +5,10 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/tidwall/gjson"
 	"gopkg.in/yaml.v2"
 
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/idgen"
@@ -17,6 +19,20 @@ import (
 
 const LogstashInstanceDirectoryPrefix = "logstash-instance"
 
+// quietPeriod is the time, after which a test with an unknown number of
+// expected events is considered complete, if no new events arrive.
+const quietPeriod = time.Second
+
+// firstEventTimeout is the time, a test with an unknown number of expected
+// events waits for the first event, before the quiet period starts. This
+// prevents slow pipelines (e.g. with lookups in external systems) from
+// completing the test before the first event arrives.
+const firstEventTimeout = 10 * time.Second
+
+// firstEventPollInterval is the interval, in which the arrival of the first
+// event is checked.
+const firstEventPollInterval = 50 * time.Millisecond
+
 type Controller struct {
 	id string
 
@@ -33,7 +49,11 @@ type Controller struct {
 	waitForLateArrivalsTimeout time.Duration
 
 	receivedEvents *events
+	receivedLogs   *events
 	pipelines      *pipelines
+
+	timings *timings
+	apiPort atomic.Int32
 }
 
 func NewController(instance Instance, baseDir string, log logging.Logger, waitForStateTimeout time.Duration, isOrderedPipelineSupported bool, waitForLateArrivalsTimeout time.Duration) (*Controller, error) {
@@ -78,7 +98,10 @@ func NewController(instance Instance, baseDir string, log logging.Logger, waitFo
 		waitForLateArrivalsTimeout: waitForLateArrivalsTimeout,
 
 		receivedEvents: newEvents(),
+		receivedLogs:   newEvents(),
 		pipelines:      newPipelines(),
+
+		timings: newTimings(),
 	}
 
 	err = controller.writePipelines()
@@ -110,7 +133,7 @@ func (c *Controller) Launch(ctx context.Context) error {
 }
 
 func (c *Controller) SetupTest(pipelines pipeline.Pipelines) error {
-	err := c.stateMachine.waitForState(stateReady)
+	err := c.waitForState(stateReady)
 	if err != nil {
 		return err
 	}
@@ -121,21 +144,29 @@ func (c *Controller) SetupTest(pipelines pipeline.Pipelines) error {
 }
 
 func (c *Controller) ExecuteTest(pipelines pipeline.Pipelines, expectedEvents int) error {
-	err := c.stateMachine.waitForState(stateReadyForTest)
+	err := c.waitForState(stateReadyForTest)
 	if err != nil {
 		return err
 	}
 
 	c.stateMachine.executeCommand(commandExecuteTest)
+	c.timings.start()
 
 	return c.reload(pipelines, expectedEvents)
 }
 
 func (c *Controller) GetResults() ([]string, error) {
+	if c.receivedEvents.isExpectedUnknown() {
+		err := c.waitForQuietPeriod()
+		if err != nil {
+			return c.receivedEvents.get(), err
+		}
+	}
+
 	// Check if complete right away for the special case, where no event is expected.
 	c.checkComplete()
 
-	err := c.stateMachine.waitForState(stateReadyForTest)
+	err := c.waitForState(stateReadyForTest)
 	if err != nil {
 		return c.receivedEvents.get(), err
 	}
@@ -147,9 +178,81 @@ func (c *Controller) GetResults() ([]string, error) {
 	return c.receivedEvents.get(), nil
 }
 
+// StreamResults passes the events and the Logstash log lines with level
+// WARN or above of the current test to onEvent and onLog as soon as they
+// arrive. StreamResults returns, when the test is complete (see
+// GetResults) or if onEvent or onLog return an error.
+func (c *Controller) StreamResults(onEvent func(event string) error, onLog func(line string) error) error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.GetResults()
+		done <- err
+	}()
+
+	sentEvents, sentLogs := 0, 0
+	flush := func() error {
+		for _, event := range c.receivedEvents.since(sentEvents) {
+			sentEvents++
+			if err := onEvent(event); err != nil {
+				return err
+			}
+		}
+		for _, line := range c.receivedLogs.since(sentLogs) {
+			sentLogs++
+			if err := onLog(line); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for {
+		select {
+		case <-c.receivedEvents.changed():
+		case <-c.receivedLogs.changed():
+		case err := <-done:
+			if flushErr := flush(); flushErr != nil {
+				return flushErr
+			}
+			return err
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// waitForQuietPeriod completes a test with an unknown number of expected
+// events, as soon as no new events have arrived for the quiet period. The
+// quiet period starts with the first event or, if no event arrives, after
+// firstEventTimeout.
+func (c *Controller) waitForQuietPeriod() error {
+	err := c.waitForState(stateRunningTest)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(firstEventTimeout)
+	for c.receivedEvents.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(firstEventPollInterval)
+	}
+
+	received := -1
+	for received != c.receivedEvents.count() {
+		received = c.receivedEvents.count()
+		time.Sleep(quietPeriod)
+	}
+
+	c.timings.setCompleted()
+	c.stateMachine.executeCommand(commandTestComplete)
+	return nil
+}
+
 func (c *Controller) Teardown() error {
 	err := c.stateMachine.waitForState(stateReadyForTest)
-	if err != nil {
+	// The pipelines, which failed to load, are removed by the teardown as
+	// well.
+	if err != nil && err != errPipelinesFailed {
 		return err
 	}
 
@@ -158,6 +261,16 @@ func (c *Controller) Teardown() error {
 	return c.reload(nil, 0)
 }
 
+// waitForState waits for the target state. If Logstash failed to load the
+// pipelines in the meantime, the errors of the pipelines are returned.
+func (c *Controller) waitForState(target stateName) error {
+	err := c.stateMachine.waitForState(target)
+	if err == errPipelinesFailed {
+		return c.pipelines.loadError()
+	}
+	return err
+}
+
 func (c *Controller) reload(pipelines pipeline.Pipelines, expectedEvents int) error {
 	err := c.writePipelines(pipelines...)
 	if err != nil {
@@ -170,6 +283,7 @@ func (c *Controller) reload(pipelines pipeline.Pipelines, expectedEvents int) er
 	}
 
 	c.receivedEvents.reset(expectedEvents)
+	c.receivedLogs.reset(0)
 	c.pipelines.reset(pipelineNames...)
 
 	err = c.instance.ConfigReload()
@@ -198,17 +312,32 @@ func (c *Controller) writePipelines(pipelines ...pipeline.Pipeline) error {
 	return nil
 }
 
+// PipelinesConfig returns the pipelines.yml last loaded by Logstash.
+func (c *Controller) PipelinesConfig() ([]byte, error) {
+	return os.ReadFile(filepath.Join(c.workDir, "pipelines.yml"))
+}
+
 func (c *Controller) ReceiveEvent(event string) {
 	c.receivedEvents.append(event)
 
 	c.checkComplete()
 }
 
+// ReceiveLog receives a line of the Logstash log in JSON format. Lines with
+// level WARN or above are kept for StreamResults until the next reload.
+func (c *Controller) ReceiveLog(line string) {
+	switch gjson.Get(line, "level").String() {
+	case "WARN", "ERROR", "FATAL":
+		c.receivedLogs.append(line)
+	}
+}
+
 func (c *Controller) checkComplete() {
 	if c.receivedEvents.isCompleteFirstTime() {
 		go func() {
 			_ = c.stateMachine.waitForState(stateRunningTest)
 
+			c.timings.setCompleted()
 			c.stateMachine.executeCommand(commandTestComplete)
 		}()
 	}
@@ -221,10 +350,26 @@ func (c *Controller) PipelinesReady(pipelines ...string) {
 			c.log.Info("Ready to process tests")
 		})
 
+		c.timings.setReady()
 		c.stateMachine.executeCommand(commandPipelineReady)
 	}
 }
 
+// PipelineFailed receives the error of a pipeline, which Logstash failed to
+// load. While a test is set up or executed, the error is returned to the
+// caller waiting for the pipelines to be ready.
+func (c *Controller) PipelineFailed(pipelineID string, message string) {
+	switch c.stateMachine.getState() {
+	case stateSettingUpTest, stateExecutingTest, stateFailed:
+	default:
+		c.log.Warningf("Logstash failed to load pipeline %q: %s", pipelineID, message)
+		return
+	}
+
+	c.pipelines.setFailed(pipelineID, message)
+	c.stateMachine.executeCommand(commandPipelineFail)
+}
+
 func (c *Controller) SignalCrash() {
 	c.stateMachine.executeCommand(commandCrash)
 	c.Kill()
@@ -234,6 +379,15 @@ func (c *Controller) Kill() {
 	c.shutdown()
 }
 
+// State returns the current state of the Logstash controller (e.g. ready,
+// running_test).
+func (c *Controller) State() string {
+	if c.stateMachine == nil {
+		return stateCreated.String()
+	}
+	return c.stateMachine.getState().String()
+}
+
 func (c *Controller) IsHealthy() bool {
 	return c.stateMachine.getState() != stateUnknown
 }
@@ -3,7 +3,11 @@ package controller_test
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -154,6 +158,10 @@ func TestCompleteCycle(t *testing.T) {
 			is.NoErr(err)
 			is.Equal(2, len(res))
 
+			reload, processing := c.ExecutionTimes()
+			is.True(reload > 0)     // reload time recorded
+			is.True(processing > 0) // processing time recorded
+
 			// Test content of pipeline.yml
 			is.True(file.Exists(filepath.Join(tempdir, controller.LogstashInstanceDirectoryPrefix, c.ID(), "pipelines.yml")))                // pipelines.yml
 			is.True(file.Contains(filepath.Join(tempdir, controller.LogstashInstanceDirectoryPrefix, c.ID(), "pipelines.yml"), "id: main"))  // pipelines.yml contains "id: main"
@@ -218,3 +226,202 @@ func TestSetupTest_Shutdown(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamResults(t *testing.T) {
+	is := is.New(t)
+
+	instance := &mock.InstanceMock{
+		StartFunc: func(ctx context.Context, controllerMoqParam *controller.Controller, workdir string) error {
+			return nil
+		},
+		ConfigReloadFunc: func() error {
+			return nil
+		},
+	}
+
+	tempdir := t.TempDir()
+
+	c, err := controller.NewController(instance, tempdir, logging.NoopLogger, defaultWaitForStateTimeout, true, defaultWaitForLateArrivalsTimeout)
+	is.NoErr(err)
+
+	err = c.Launch(context.Background())
+	is.NoErr(err)
+
+	c.PipelinesReady("stdin", "output", "__lfv_pipelines_running")
+
+	pipelines := pipeline.Pipelines{
+		pipeline.Pipeline{
+			ID:      "main",
+			Config:  "main.conf",
+			Workers: 1,
+		},
+	}
+
+	err = c.SetupTest(pipelines)
+	is.NoErr(err)
+
+	c.PipelinesReady("stdin", "output", "main", "__lfv_pipelines_running")
+
+	// Log lines before the test is executed are not streamed.
+	c.ReceiveLog(`{"level":"WARN","logEvent":{"message":"before test"}}`)
+
+	err = c.ExecuteTest(pipelines, 2)
+	is.NoErr(err)
+
+	c.PipelinesReady("stdin", "output", "main", "__lfv_pipelines_running")
+	c.ReceiveEvent(`{ "message": "result 1" }`)
+	c.ReceiveLog(`{"level":"INFO","logEvent":{"message":"info"}}`)
+	c.ReceiveLog(`{"level":"ERROR","logEvent":{"message":"error"}}`)
+
+	var events, logs []string
+	err = c.StreamResults(
+		func(event string) error {
+			events = append(events, event)
+			if len(events) == 1 {
+				// The second event arrives, while the results are streamed.
+				go c.ReceiveEvent(`{ "message": "result 2" }`)
+			}
+			return nil
+		},
+		func(line string) error {
+			logs = append(logs, line)
+			return nil
+		},
+	)
+	is.NoErr(err)
+	is.Equal(events, []string{`{ "message": "result 1" }`, `{ "message": "result 2" }`})
+	is.Equal(logs, []string{`{"level":"ERROR","logEvent":{"message":"error"}}`}) // only log lines with level WARN or above
+}
+
+func TestPipelineFailed(t *testing.T) {
+	is := is.New(t)
+
+	instance := &mock.InstanceMock{
+		StartFunc: func(ctx context.Context, controllerMoqParam *controller.Controller, workdir string) error {
+			return nil
+		},
+		ConfigReloadFunc: func() error {
+			return nil
+		},
+	}
+
+	tempdir := t.TempDir()
+
+	c, err := controller.NewController(instance, tempdir, logging.NoopLogger, defaultWaitForStateTimeout, true, defaultWaitForLateArrivalsTimeout)
+	is.NoErr(err)
+
+	err = c.Launch(context.Background())
+	is.NoErr(err)
+
+	c.PipelinesReady("stdin", "output", "__lfv_pipelines_running")
+
+	pipelines := pipeline.Pipelines{
+		pipeline.Pipeline{
+			ID:      "main",
+			Config:  "main.conf",
+			Workers: 1,
+		},
+	}
+
+	err = c.SetupTest(pipelines)
+	is.NoErr(err)
+
+	c.PipelineFailed("main", "Expected one of [ \\t\\r\\n] at line 3, column 9 (byte 29)")
+
+	start := time.Now()
+	err = c.ExecuteTest(pipelines, 2)
+	is.True(time.Since(start) < defaultWaitForStateTimeout) // no timeout
+
+	var loadErr pipeline.LoadError
+	is.True(errors.As(err, &loadErr))                                                                                   // pipeline error is returned
+	is.Equal(loadErr.Messages, map[string]string{"main": "Expected one of [ \\t\\r\\n] at line 3, column 9 (byte 29)"}) // error per pipeline
+	is.Equal(c.State(), "failed")
+
+	// The teardown removes the failed pipelines and the controller can be
+	// used again.
+	err = c.Teardown()
+	is.NoErr(err)
+
+	c.PipelinesReady("stdin", "output", "__lfv_pipelines_running")
+	is.Equal(c.State(), "ready")
+}
+
+func TestPluginStats(t *testing.T) {
+	nodeStats := `{
+  "pipelines": {
+    "lfv_abc_main": {
+      "plugins": {
+        "inputs": [{"id": "in", "name": "pipeline", "events": {"out": 3, "queue_push_duration_in_millis": 1}}],
+        "filters": [{"id": "grok", "name": "grok", "events": {"in": 3, "out": 2, "duration_in_millis": 12}}],
+        "outputs": [{"id": "out", "name": "pipeline", "events": {"in": 2, "out": 2, "duration_in_millis": 4}}]
+      }
+    },
+    "lfv_abc_with.dot": {
+      "plugins": {
+        "filters": [{"id": "mutate", "name": "mutate", "events": {"in": 1, "out": 1, "duration_in_millis": 0}}]
+      }
+    },
+    "lfv_input_1": {
+      "plugins": {
+        "inputs": [{"id": "generator", "name": "generator", "events": {"out": 3}}]
+      }
+    }
+  }
+}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_node/stats/pipelines" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(nodeStats))
+	}))
+	defer server.Close()
+
+	port, err := strconv.Atoi(server.URL[strings.LastIndex(server.URL, ":")+1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		apiPort int
+
+		want    []pipeline.PluginStats
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			apiPort: port,
+
+			want: []pipeline.PluginStats{
+				{PipelineID: "lfv_abc_main", ID: "in", Name: "pipeline", Kind: "input", EventsOut: 3},
+				{PipelineID: "lfv_abc_main", ID: "grok", Name: "grok", Kind: "filter", EventsIn: 3, EventsOut: 2, Duration: 12 * time.Millisecond},
+				{PipelineID: "lfv_abc_main", ID: "out", Name: "pipeline", Kind: "output", EventsIn: 2, EventsOut: 2, Duration: 4 * time.Millisecond},
+				{PipelineID: "lfv_abc_with.dot", ID: "mutate", Name: "mutate", Kind: "filter", EventsIn: 1, EventsOut: 1},
+			},
+		},
+		{
+			name: "API endpoint not available",
+
+			wantErr: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			c, err := controller.NewController(nil, t.TempDir(), logging.NoopLogger, defaultWaitForStateTimeout, true, defaultWaitForLateArrivalsTimeout)
+			is.NoErr(err)
+
+			if test.apiPort != 0 {
+				c.SetAPIPort(test.apiPort)
+			}
+
+			stats, err := c.PluginStats("lfv_abc_main", "lfv_abc_with.dot")
+			is.Equal(err != nil, test.wantErr) // PluginStats error
+			is.Equal(stats, test.want)
+		})
+	}
+}
@@ -9,12 +9,16 @@ type events struct {
 	completeFirstTime bool
 	expected          int
 	mutex             *sync.Mutex
+
+	// notify receives a signal, if a new event has been appended.
+	notify chan struct{}
 }
 
 func newEvents() *events {
 	return &events{
 		events: make([]string, 0, 100),
 		mutex:  &sync.Mutex{},
+		notify: make(chan struct{}, 1),
 	}
 }
 
@@ -23,6 +27,31 @@ func (e *events) append(event string) {
 	defer e.mutex.Unlock()
 
 	e.events = append(e.events, event)
+
+	select {
+	case e.notify <- struct{}{}:
+	default:
+	}
+}
+
+// changed returns a channel, which receives a signal, if new events have
+// been appended since the last signal.
+func (e *events) changed() <-chan struct{} {
+	return e.notify
+}
+
+// since returns the events starting at index i.
+func (e *events) since(i int) []string {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if i >= len(e.events) {
+		return nil
+	}
+	results := make([]string, 0, len(e.events)-i)
+	results = append(results, e.events[i:]...)
+
+	return results
 }
 
 func (e *events) isCompleteFirstTime() bool {
@@ -37,6 +66,22 @@ func (e *events) isCompleteFirstTime() bool {
 	return false
 }
 
+// isExpectedUnknown returns true, if the number of expected events is not
+// known in advance, which is indicated by a negative number.
+func (e *events) isExpectedUnknown() bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.expected < 0
+}
+
+func (e *events) count() int {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return len(e.events)
+}
+
 func (e *events) reset(expected int) {
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
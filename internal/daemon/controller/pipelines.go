@@ -1,15 +1,21 @@
 package controller
 
-import "sync"
+import (
+	"sync"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pipeline"
+)
 
 type pipelines struct {
 	pipelines map[string]bool
+	failed    map[string]string
 	mutex     *sync.Mutex
 }
 
 func newPipelines() *pipelines {
 	return &pipelines{
 		pipelines: make(map[string]bool),
+		failed:    make(map[string]string),
 		mutex:     &sync.Mutex{},
 	}
 }
@@ -20,6 +26,7 @@ func (p *pipelines) reset(pipelines ...string) {
 
 	p.pipelines = make(map[string]bool, len(pipelines)+1)
 	p.pipelines["__lfv_pipelines_running"] = false
+	p.failed = make(map[string]string)
 
 	for _, pipeline := range pipelines {
 		p.pipelines[pipeline] = false
@@ -43,3 +50,22 @@ func (p *pipelines) isReady() bool {
 	}
 	return true
 }
+
+func (p *pipelines) setFailed(id string, message string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.failed[id] = message
+}
+
+// loadError returns the errors of the pipelines, which failed to load.
+func (p *pipelines) loadError() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	messages := make(map[string]string, len(p.failed))
+	for id, message := range p.failed {
+		messages[id] = message
+	}
+	return pipeline.LoadError{Messages: messages}
+}
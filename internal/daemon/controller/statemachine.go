@@ -10,6 +10,10 @@ import (
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
 )
 
+// errPipelinesFailed is returned by waitForState, if Logstash failed to load
+// the pipelines of the test.
+var errPipelinesFailed = errors.New("Logstash failed to load the pipelines")
+
 type stateMachine struct {
 	ctx context.Context
 
@@ -86,6 +90,9 @@ func (s *stateMachine) waitForState(target stateName) error {
 		if s.currentState == stateUnknown {
 			return errors.Errorf("state unknown, failed to wait for state: %s", target)
 		}
+		if s.currentState == stateFailed {
+			return errPipelinesFailed
+		}
 
 		s.cond.Wait()
 	}
@@ -128,6 +135,7 @@ const (
 	stateReadyForTest  stateName = "ready_for_test"
 	stateExecutingTest stateName = "executing_test"
 	stateRunningTest   stateName = "running_test"
+	stateFailed        stateName = "failed"
 )
 
 func (s stateName) String() string {
@@ -139,6 +147,7 @@ type command string
 const (
 	commandStart         command = "start"
 	commandPipelineReady command = "pipeline-ready"
+	commandPipelineFail  command = "pipeline-fail"
 	commandSetupTest     command = "setup-test"
 	commandExecuteTest   command = "execute-test"
 	commandTestComplete  command = "test-complete"
@@ -166,6 +175,7 @@ var stateTransitionsTable = map[stateCommand]transitionFunc{
 
 	// State LoadingSUT
 	{stateSettingUpTest, commandPipelineReady}: func() stateName { return stateReadyForTest },
+	{stateSettingUpTest, commandPipelineFail}:  func() stateName { return stateFailed },
 
 	// State Ready for Test
 	{stateReadyForTest, commandExecuteTest}: func() stateName { return stateExecutingTest },
@@ -173,11 +183,19 @@ var stateTransitionsTable = map[stateCommand]transitionFunc{
 
 	// State Starting Test
 	{stateExecutingTest, commandPipelineReady}: func() stateName { return stateRunningTest },
+	{stateExecutingTest, commandPipelineFail}:  func() stateName { return stateFailed },
 
 	// State Running Test
 	{stateRunningTest, commandPipelineReady}: func() stateName { return stateRunningTest },
 	{stateRunningTest, commandTestComplete}:  func() stateName { return stateReadyForTest },
 
+	// State Failed, the pipelines, which failed to load, are removed by the
+	// teardown.
+	{stateFailed, commandPipelineReady}: func() stateName { return stateFailed },
+	{stateFailed, commandPipelineFail}:  func() stateName { return stateFailed },
+	{stateFailed, commandTestComplete}:  func() stateName { return stateFailed },
+	{stateFailed, commandTeardown}:      func() stateName { return stateStarted },
+
 	// State Unknown is the fallback state for all undefined state transitions.
 	// {*, *}:  func() stateName { return stateUnknown },
 }
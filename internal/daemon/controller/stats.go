@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pipeline"
+)
+
+// nodeStatsTimeout is the timeout for requests to the Logstash node stats
+// API.
+const nodeStatsTimeout = 5 * time.Second
+
+// timings records the points in time of a test execution.
+type timings struct {
+	started   time.Time
+	ready     time.Time
+	completed time.Time
+	mutex     *sync.Mutex
+}
+
+func newTimings() *timings {
+	return &timings{
+		mutex: &sync.Mutex{},
+	}
+}
+
+func (t *timings) start() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.started = time.Now()
+	t.ready = time.Time{}
+	t.completed = time.Time{}
+}
+
+// setReady records the first time, the pipelines are ready after the start
+// of the test execution.
+func (t *timings) setReady() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.started.IsZero() || !t.ready.IsZero() {
+		return
+	}
+	t.ready = time.Now()
+}
+
+// setCompleted records the first time, the test execution is complete.
+func (t *timings) setCompleted() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.ready.IsZero() || !t.completed.IsZero() {
+		return
+	}
+	t.completed = time.Now()
+}
+
+func (t *timings) durations() (reload time.Duration, processing time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if !t.ready.IsZero() {
+		reload = t.ready.Sub(t.started)
+	}
+	if !t.completed.IsZero() {
+		processing = t.completed.Sub(t.ready)
+	}
+	return reload, processing
+}
+
+// ExecutionTimes returns the durations of the last test execution. reload
+// is the time from the start of the test execution until the pipelines are
+// running, processing is the time from then until the test is complete.
+// For tests with an unknown number of expected events, processing includes
+// the quiet period.
+func (c *Controller) ExecutionTimes() (reload time.Duration, processing time.Duration) {
+	return c.timings.durations()
+}
+
+// SetAPIPort sets the port of the Logstash API endpoint, which is used to
+// query the node stats.
+func (c *Controller) SetAPIPort(port int) {
+	c.apiPort.Store(int32(port))
+}
+
+// PluginStats returns the statistics of the plugins of the given pipelines
+// from the Logstash node stats API. The statistics of a pipeline are
+// accumulated since the pipeline has been (re)loaded.
+func (c *Controller) PluginStats(pipelineIDs ...string) ([]pipeline.PluginStats, error) {
+	port := c.apiPort.Load()
+	if port == 0 {
+		return nil, errors.New("Logstash API endpoint is not available")
+	}
+
+	client := http.Client{
+		Timeout: nodeStatsTimeout,
+	}
+	resp, err := client.Get(fmt.Sprintf("http://127.0.0.1:%d/_node/stats/pipelines", port))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get Logstash node stats")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to get Logstash node stats: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read Logstash node stats")
+	}
+
+	return parsePluginStats(body, pipelineIDs), nil
+}
+
+func parsePluginStats(body []byte, pipelineIDs []string) []pipeline.PluginStats {
+	var stats []pipeline.PluginStats
+	for _, pipelineID := range pipelineIDs {
+		plugins := gjson.GetBytes(body, "pipelines."+escapePath(pipelineID)+".plugins")
+		for _, kind := range []string{"input", "filter", "output"} {
+			for _, plugin := range plugins.Get(kind + "s").Array() {
+				stats = append(stats, pipeline.PluginStats{
+					PipelineID: pipelineID,
+					ID:         plugin.Get("id").String(),
+					Name:       plugin.Get("name").String(),
+					Kind:       kind,
+					EventsIn:   plugin.Get("events.in").Int(),
+					EventsOut:  plugin.Get("events.out").Int(),
+					Duration:   time.Duration(plugin.Get("events.duration_in_millis").Int()) * time.Millisecond,
+				})
+			}
+		}
+	}
+	return stats
+}
+
+// escapePath escapes the characters with a special meaning in a gjson path.
+func escapePath(key string) string {
+	var b strings.Builder
+	for _, r := range key {
+		if strings.ContainsRune(`.*?|#@!\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -3,6 +3,7 @@ package logstash
 import (
 	"bufio"
 	"io"
+	"regexp"
 	"strings"
 
 	"github.com/hpcloud/tail"
@@ -84,6 +85,8 @@ func (i *instance) logstashLogProcessor(t *tail.Tail) {
 	for {
 		select {
 		case line := <-t.Lines:
+			i.controller.ReceiveLog(line.Text)
+
 			switch gjson.Get(line.Text, "logEvent.message").String() {
 			case "Pipeline started":
 				pipelineID := gjson.Get(line.Text, `logEvent.pipeline\.id`).String()
@@ -109,6 +112,17 @@ func (i *instance) logstashLogProcessor(t *tail.Tail) {
 
 				i.controller.PipelinesReady(runningPipelines...)
 				i.controller.PipelinesReady("__lfv_pipelines_running")
+			case "Failed to execute action", "An exception happened when converging configuration", "Pipeline error":
+				pipelineID := failedPipelineID(line.Text)
+				message := failureMessage(line.Text)
+				i.log.Debugf("taillog: -> pipeline failed: %s: %s", pipelineID, message)
+
+				i.controller.PipelineFailed(pipelineID, message)
+			case "Successfully started Logstash API endpoint":
+				port := gjson.Get(line.Text, `logEvent.port`).Int()
+				i.log.Debugf("taillog: -> API endpoint started on port: %d", port)
+
+				i.controller.SetAPIPort(int(port))
 			}
 		case <-i.ctxShutdown.Done():
 			i.log.Debug("shutdown log reader")
@@ -117,6 +131,55 @@ func (i *instance) logstashLogProcessor(t *tail.Tail) {
 	}
 }
 
+// pipelineIDRe matches the pipeline ID in the string representation of a
+// pipeline action, e.g. "LogStash::PipelineAction::Create/pipeline_id:main"
+// or "PipelineAction::Create<main>".
+var pipelineIDRe = regexp.MustCompile(`pipeline_id:([\w.-]+)|PipelineAction::\w+<([\w.-]+)>`)
+
+// failedPipelineID returns the ID of the pipeline, a log line about a
+// failure refers to. Depending on the version of Logstash, the pipeline ID
+// is logged as a field of its own or only as part of the pipeline action.
+func failedPipelineID(line string) string {
+	for _, path := range []string{`logEvent.id`, `logEvent.pipeline\.id`, `logEvent.pipeline_id`} {
+		if id := gjson.Get(line, path); id.Type == gjson.String {
+			return id.String()
+		}
+	}
+
+	m := pipelineIDRe.FindStringSubmatch(line)
+	if m == nil {
+		return ""
+	}
+	if m[1] != "" {
+		return m[1]
+	}
+	return m[2]
+}
+
+// failureMessage returns the error message of a log line about a failure.
+// Logstash logs the details of the failure as an additional message or as
+// exception.
+func failureMessage(line string) string {
+	logEvent := gjson.Get(line, "logEvent")
+
+	var messages []string
+	logEvent.ForEach(func(key, value gjson.Result) bool {
+		if key.String() == "message" {
+			messages = append(messages, value.String())
+		}
+		return true
+	})
+	if len(messages) > 1 {
+		return strings.Join(messages[1:], ": ")
+	}
+
+	message := logEvent.Get("message").String()
+	if exception := logEvent.Get("exception").String(); exception != "" {
+		message += ": " + exception
+	}
+	return message
+}
+
 func extractPipelines(in string) []string {
 	if len(in) < 3 {
 		return nil
@@ -36,3 +36,51 @@ func TestExtractPipelines(t *testing.T) {
 		})
 	}
 }
+
+func TestFailure(t *testing.T) {
+	cases := []struct {
+		name string
+		line string
+
+		wantID      string
+		wantMessage string
+	}{
+		{
+			name: "failed action with pipeline id",
+			line: `{"level":"ERROR","loggerName":"logstash.agent","logEvent":{"message":"Failed to execute action","id":"lfv_ukPSsPZk_main","action_type":"LogStash::ConvergeResult::FailedAction","message":"Expected one of [ \\t\\r\\n], \"#\", \"{\" at line 3, column 9 (byte 29) after filter {\n  mutate ","backtrace":null}}`,
+
+			wantID:      "lfv_ukPSsPZk_main",
+			wantMessage: "Expected one of [ \\t\\r\\n], \"#\", \"{\" at line 3, column 9 (byte 29) after filter {\n  mutate ",
+		},
+		{
+			name: "failed action with pipeline action",
+			line: `{"level":"ERROR","loggerName":"logstash.agent","logEvent":{"message":"Failed to execute action","action":"LogStash::PipelineAction::Create/pipeline_id:lfv_ukPSsPZk_main-test","exception":"LogStash::ConfigurationError","message":"Expected one of [ \\t\\r\\n] at line 1, column 1 (byte 1)"}}`,
+
+			wantID:      "lfv_ukPSsPZk_main-test",
+			wantMessage: "Expected one of [ \\t\\r\\n] at line 1, column 1 (byte 1)",
+		},
+		{
+			name: "convergence error",
+			line: `{"level":"ERROR","loggerName":"logstash.agent","logEvent":{"message":"An exception happened when converging configuration","exception":"LogStash::Error","message":"Don't know how to handle ` + "`Java::JavaLang::IllegalStateException` for `PipelineAction::Create<lfv_ukPSsPZk_main>`" + `"}}`,
+
+			wantID:      "lfv_ukPSsPZk_main",
+			wantMessage: "Don't know how to handle `Java::JavaLang::IllegalStateException` for `PipelineAction::Create<lfv_ukPSsPZk_main>`",
+		},
+		{
+			name: "pipeline error",
+			line: `{"level":"ERROR","loggerName":"logstash.javapipeline","logEvent":{"message":"Pipeline error","pipeline_id":"lfv_ukPSsPZk_main","exception":"#<RegexpError: premature end of char-class>"}}`,
+
+			wantID:      "lfv_ukPSsPZk_main",
+			wantMessage: "Pipeline error: #<RegexpError: premature end of char-class>",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			is.Equal(failedPipelineID(test.line), test.wantID)
+			is.Equal(failureMessage(test.line), test.wantMessage)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package logstashconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/breml/logstash-config/ast"
+)
+
+// DropPrefix is the prefix of the IDs of the plugins added by
+// InstrumentDrops.
+const DropPrefix = "__lfv_drop_"
+
+// DroppedMessage is the message of the Logstash log lines, which report
+// an event about to be dropped by a drop filter. The log line contains the
+// ID of the drop filter (id), the Logstash config file (file), the
+// conditional branches leading to the drop filter (branch) and the LFV ID
+// of the event (event).
+const DroppedMessage = "__lfv_dropped"
+
+// dropCode logs the event, which is about to be dropped. The level WARN is
+// required for the log line to be passed on by the daemon.
+const dropCode = `logger.warn("` + DroppedMessage + `", "id" => "%s", "file" => "%s", "branch" => "%s", "event" => event.get("[@metadata][__lfv_id]").to_s)`
+
+// InstrumentDrops adds a ruby filter before each drop filter, which logs
+// the events dropped by the drop filter together with the conditional
+// branches leading to it (see DroppedMessage). Drop filters with the
+// percentage option are not instrumented, since they do not necessarily
+// drop the event.
+func (f *File) InstrumentDrops() error {
+	err := f.parse()
+	if err != nil {
+		return err
+	}
+
+	d := dropInstrumenter{
+		file: strings.TrimPrefix(f.Name, "/"),
+	}
+	for i := range f.config.Filter {
+		f.config.Filter[i].BranchOrPlugins = d.block(nil, f.config.Filter[i].BranchOrPlugins)
+	}
+
+	// Keep the Logstash config as is, if there is no drop filter.
+	if d.count > 0 {
+		f.serialize()
+	}
+
+	return nil
+}
+
+type dropInstrumenter struct {
+	file  string
+	count int
+}
+
+// block instruments the drop filters of a block. branches contains the
+// conditions of the branches, the block is nested in.
+func (d *dropInstrumenter) block(branches []string, bops []ast.BranchOrPlugin) []ast.BranchOrPlugin {
+	result := make([]ast.BranchOrPlugin, 0, len(bops))
+	for _, bop := range bops {
+		switch node := bop.(type) {
+		case ast.Plugin:
+			if node.Name() != "drop" || hasAttribute(node, "percentage") {
+				break
+			}
+			id, err := node.ID()
+			if err != nil {
+				break
+			}
+			result = append(result, dropPlugin(id, d.file, strings.Join(branches, " > ")))
+			d.count++
+		case ast.Branch:
+			node.IfBlock.Block = d.block(nested(branches, "if "+node.IfBlock.Condition.String()), node.IfBlock.Block)
+			for j := range node.ElseIfBlock {
+				node.ElseIfBlock[j].Block = d.block(nested(branches, "else if "+node.ElseIfBlock[j].Condition.String()), node.ElseIfBlock[j].Block)
+			}
+			node.ElseBlock.Block = d.block(nested(branches, "else"), node.ElseBlock.Block)
+			bop = node
+		}
+		result = append(result, bop)
+	}
+	return result
+}
+
+// nested returns the branches extended by branch without modifying the
+// underlying array of branches.
+func nested(branches []string, branch string) []string {
+	return append(branches[:len(branches):len(branches)], branch)
+}
+
+func hasAttribute(plugin ast.Plugin, name string) bool {
+	for _, attr := range plugin.Attributes {
+		if attr != nil && attr.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// dropPlugin returns the ruby filter, which logs the events dropped by the
+// drop filter with the given ID.
+func dropPlugin(droppedID string, file string, branch string) ast.Plugin {
+	code := fmt.Sprintf(dropCode, rubyStringEscaper.Replace(droppedID), rubyStringEscaper.Replace(file), rubyStringEscaper.Replace(branch))
+
+	return ast.NewPlugin("ruby",
+		ast.NewStringAttribute("id", injectedID(DropPrefix, droppedID), ast.DoubleQuoted),
+		ast.NewStringAttribute("code", code, ast.SingleQuoted),
+		ast.NewStringAttribute("tag_on_exception", DropPrefix+"exception", ast.DoubleQuoted),
+	)
+}
@@ -1,6 +1,8 @@
 package logstashconfig
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path"
@@ -12,6 +14,7 @@ import (
 	"github.com/breml/logstash-config/ast/astutil"
 	"github.com/pkg/errors"
 
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/coverage"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/idgen"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pluginmock"
 )
@@ -20,6 +23,11 @@ type File struct {
 	Name string
 	Body []byte
 
+	// SourceMap maps the lines of Body back to the original Logstash
+	// config file, if Body has been rewritten. If SourceMap is nil, Body
+	// is the original Logstash config file.
+	SourceMap SourceMap
+
 	config *ast.Config
 }
 
@@ -67,7 +75,7 @@ func (f *File) ReplaceInputs(idPrefix string) (map[string]string, error) {
 		astutil.ApplyPlugins(f.config.Input[i].BranchOrPlugins, w.replaceInputs)
 	}
 
-	f.Body = []byte(f.config.String())
+	f.serialize()
 
 	return w.inputCodecs, nil
 }
@@ -103,7 +111,9 @@ func (r replaceInputsWalker) replaceInputs(c *astutil.Cursor) {
 		}
 	}
 
-	c.Replace(ast.NewPlugin("pipeline", attrs...))
+	plugin := ast.NewPlugin("pipeline", attrs...)
+	plugin.Start = c.Plugin().Start
+	c.Replace(plugin)
 }
 
 func (f *File) ReplaceOutputs() ([]string, error) {
@@ -119,7 +129,7 @@ func (f *File) ReplaceOutputs() ([]string, error) {
 		astutil.ApplyPlugins(f.config.Output[i].BranchOrPlugins, outputs.walk)
 	}
 
-	f.Body = []byte(f.config.String())
+	f.serialize()
 
 	return outputs.outputs, nil
 }
@@ -141,7 +151,9 @@ func (o *outputPipelineReplacer) walk(c *astutil.Cursor) {
 	outputName := fmt.Sprintf("lfv_output_%s", id)
 	o.outputs = append(o.outputs, id)
 
-	c.Replace(ast.NewPlugin("pipeline", ast.NewArrayAttribute("send_to", ast.NewStringAttribute("", outputName, ast.DoubleQuoted))))
+	plugin := ast.NewPlugin("pipeline", ast.NewArrayAttribute("send_to", ast.NewStringAttribute("", outputName, ast.DoubleQuoted)))
+	plugin.Start = c.Plugin().Start
+	c.Replace(plugin)
 }
 
 func (f *File) Validate(addMissingID bool) (inputs map[string]int, outputs map[string]int, err error) {
@@ -182,7 +194,7 @@ func (f *File) Validate(addMissingID bool) (inputs map[string]int, outputs map[s
 	}
 
 	if addMissingID {
-		f.Body = []byte(f.config.String())
+		f.serialize()
 	}
 
 	return v.inputs, v.outputs, nil
@@ -243,11 +255,36 @@ func (f *File) ApplyMocks(m pluginmock.Mocks) error {
 		f.config.Output[i].BranchOrPlugins = astutil.ApplyPlugins(f.config.Output[i].BranchOrPlugins, m.Walk)
 	}
 
-	f.Body = []byte(f.config.String())
+	f.serialize()
 
 	return nil
 }
 
+// InstrumentCoverage adds the plugins and conditional branches of the
+// config as coverage points to c and instruments the config accordingly.
+// source is the body of the original Logstash config file, before it has
+// been rewritten (e.g. by ApplyMocks).
+func (f *File) InstrumentCoverage(c *coverage.Coverage, source []byte) error {
+	err := f.parse()
+	if err != nil {
+		return err
+	}
+
+	c.Instrument(strings.TrimPrefix(f.Name, "/"), source, f.config, f.sourcePos, pluginIDSave)
+
+	f.serialize()
+
+	return nil
+}
+
+// injectedID returns the ID of a plugin added by LFV for the plugin or file
+// with the given ID. pluginIDSave alone does not keep the IDs unique (e.g.
+// parse-ip and parse_ip), therefore a hash of the original ID is appended.
+func injectedID(prefix string, id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return prefix + pluginIDSave(id) + "_" + hex.EncodeToString(sum[:4])
+}
+
 func pluginIDSave(in string) string {
 	return strings.Map(func(r rune) rune {
 		switch {
@@ -3,14 +3,17 @@ package logstashconfig_test
 import (
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/matryer/is"
 	"github.com/pkg/errors"
 
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/coverage"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/file"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/logstashconfig"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pluginmock"
 )
 
 func TestSave(t *testing.T) {
@@ -267,3 +270,166 @@ func compareErr(t *testing.T, wantErr, err error) {
 		t.Errorf("expect error to be %q, got: %q", wantErr.Error(), err.Error())
 	}
 }
+
+func TestInstrumentTrace(t *testing.T) {
+	is := is.New(t)
+
+	f := logstashconfig.File{
+		Name: "main.conf",
+		Body: []byte(`filter {
+  if [type] == "a" {
+    mutate { id => "__lfv_coverage_1" }
+    grok { id => parse }
+  }
+  mutate { id => tag }
+}
+output { stdout { id => out } }
+`),
+	}
+
+	err := f.InstrumentTrace()
+	is.NoErr(err)
+
+	body := string(f.Body)
+	is.True(strings.HasPrefix(body, `filter {
+  ruby {
+    id => "__lfv_trace_start_main_conf_0_`)) // start marker
+	is.True(strings.Contains(body, `    grok {
+      id => parse
+    }
+
+    ruby {
+      id => "__lfv_trace_parse_`)) // trace after plugin within branch
+	is.True(strings.Contains(body, `"id" => "tag", "plugin" => "mutate"`)) // traced plugin in ruby code
+	is.True(!strings.Contains(body, "__lfv_trace___lfv_coverage_1"))       // coverage markers are not traced
+	is.Equal(strings.Count(body, "ruby {"), 3)
+}
+
+func TestInstrumentUniqueIDs(t *testing.T) {
+	is := is.New(t)
+
+	f := logstashconfig.File{
+		Name: "main.conf",
+		Body: []byte(`filter {
+  mutate { id => "parse-ip" }
+  mutate { id => "parse_ip" }
+  drop { id => "drop-it" }
+  drop { id => "drop_it" }
+}
+`),
+	}
+
+	err := f.InstrumentTrace()
+	is.NoErr(err)
+	err = f.InstrumentDrops()
+	is.NoErr(err)
+
+	ids := map[string]bool{}
+	for _, m := range regexp.MustCompile(`\bid => "(__lfv_[^"]+)"`).FindAllStringSubmatch(string(f.Body), -1) {
+		is.True(!ids[m[1]]) // injected ID is unique
+		ids[m[1]] = true
+	}
+	is.Equal(len(ids), 7) // start marker, 4 traces, 2 drops
+}
+
+func TestInstrumentCoverageRewritten(t *testing.T) {
+	is := is.New(t)
+
+	source := []byte(`filter { mutate { id => "tag" } if [a] { drop { id => "drop" } } }
+`)
+	f := logstashconfig.File{
+		Name: "main.conf",
+		Body: source,
+	}
+
+	err := f.ApplyMocks(pluginmock.Mocks{})
+	is.NoErr(err)
+	is.True(string(f.Body) != string(source)) // config is reformatted
+
+	c := coverage.New()
+	err = f.InstrumentCoverage(c, source)
+	is.NoErr(err)
+
+	files := c.Files()
+	is.Equal(len(files), 1)
+	for _, p := range files[0].Points {
+		is.Equal(p.Line, 1) // position in the original config file
+	}
+}
+
+func TestInstrumentDrops(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+
+		wantContains []string
+		wantRuby     int
+		wantSame     bool
+	}{
+		{
+			name: "drop within nested branches",
+			body: `filter {
+  if [type] == "web" {
+    if [path] == "/health" {
+      drop { id => drop_healthchecks }
+    } else {
+      drop { id => drop_other }
+    }
+  }
+}
+output { stdout { id => out } }
+`,
+
+			wantContains: []string{
+				`    if [path] == "/health" {
+      ruby {
+        id => "__lfv_drop_drop_healthchecks_`,
+				`"id" => "drop_healthchecks", "file" => "filters/10-web.conf", "branch" => "if [type] == \"web\" > if [path] == \"/health\""`,
+				`"id" => "drop_other", "file" => "filters/10-web.conf", "branch" => "if [type] == \"web\" > else"`,
+			},
+			wantRuby: 2,
+		},
+		{
+			name: "drop with percentage",
+			body: `filter {
+  drop { id => sample percentage => 50 }
+}
+`,
+
+			wantSame: true,
+		},
+		{
+			name: "no drop",
+			body: `filter {
+  mutate { id => tag }
+}
+`,
+
+			wantSame: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			f := logstashconfig.File{
+				Name: "/filters/10-web.conf",
+				Body: []byte(test.body),
+			}
+
+			err := f.InstrumentDrops()
+			is.NoErr(err)
+
+			body := string(f.Body)
+			if test.wantSame {
+				is.Equal(body, test.body)
+				return
+			}
+			for _, want := range test.wantContains {
+				is.True(strings.Contains(body, want)) // instrumented config contains expected snippet
+			}
+			is.Equal(strings.Count(body, "ruby {"), test.wantRuby)
+		})
+	}
+}
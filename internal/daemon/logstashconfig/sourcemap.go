@@ -0,0 +1,228 @@
+package logstashconfig
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	config "github.com/breml/logstash-config"
+	"github.com/breml/logstash-config/ast"
+)
+
+// Position is a position in an original Logstash config file.
+type Position struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// SourceMap maps the lines of a Logstash config file, which has been
+// rewritten by LFV, back to the original Logstash config file. The mapping
+// is done on the level of plugin sections, conditional branches and
+// plugins, since the formatting within them is not retained.
+type SourceMap []SourceMapping
+
+// SourceMapping maps the plugin section, branch or plugin starting at Line
+// of the rewritten Logstash config file to its position in the original
+// Logstash config file. Source is nil for the plugins added by LFV.
+type SourceMapping struct {
+	Line   int       `json:"line"`
+	Source *Position `json:"source,omitempty"`
+}
+
+// Lookup returns the original position of the plugin section, branch or
+// plugin, the given line of the rewritten Logstash config file belongs to.
+func (m SourceMap) Lookup(line int) (Position, bool) {
+	i := sort.Search(len(m), func(i int) bool {
+		return m[i].Line > line
+	}) - 1
+	if i < 0 || m[i].Source == nil {
+		return Position{}, false
+	}
+	return *m[i].Source, true
+}
+
+// Source returns the position in the original Logstash config file for the
+// given position in Body. If Body has been rewritten, the position of the
+// enclosing plugin section, branch or plugin is returned.
+func (f File) Source(line int, column int) (Position, bool) {
+	if f.SourceMap == nil {
+		return Position{
+			File:   strings.TrimPrefix(f.Name, "/"),
+			Line:   line,
+			Column: column,
+		}, true
+	}
+	return f.SourceMap.Lookup(line)
+}
+
+// sourcePos returns the position in the original Logstash config file for
+// the given position in Body or the zero position, if it is unknown.
+func (f File) sourcePos(pos ast.Pos) ast.Pos {
+	source, ok := f.Source(pos.Line, pos.Column)
+	if !ok {
+		return ast.Pos{}
+	}
+	return ast.Pos{Line: source.Line, Column: source.Column}
+}
+
+// serialize updates Body from the modified config and extends the source
+// map by the changed positions, such that positions in Body can still be
+// traced back to the original Logstash config file.
+func (f *File) serialize() {
+	before := nodePositions(f.config)
+	f.Body = []byte(f.config.String())
+
+	icfg, err := config.Parse(f.Name, f.Body)
+	cfg, ok := icfg.(ast.Config)
+	if err != nil || !ok {
+		// Without the positions in the new Body, the lines can no longer be
+		// mapped.
+		f.SourceMap = SourceMap{}
+		return
+	}
+	after := nodePositions(&cfg)
+
+	sourceMap := SourceMap{}
+	if len(before) == len(after) {
+		for i := range after {
+			if after[i].Line == 0 {
+				continue
+			}
+			mapping := SourceMapping{
+				Line: after[i].Line,
+			}
+			if before[i].Line > 0 {
+				if source, ok := f.Source(before[i].Line, before[i].Column); ok {
+					mapping.Source = &source
+				}
+			}
+			sourceMap = append(sourceMap, mapping)
+		}
+		sort.SliceStable(sourceMap, func(i, j int) bool {
+			return sourceMap[i].Line < sourceMap[j].Line
+		})
+	}
+
+	f.SourceMap = sourceMap
+	f.config = &cfg
+}
+
+// nodePositions returns the positions of the plugin sections, branches and
+// plugins of the config in a deterministic order, which does not depend on
+// the formatting of the config. Nodes without position (e.g. added plugins
+// or missing else blocks) are included with the zero position.
+func nodePositions(cfg *ast.Config) []ast.Pos {
+	var positions []ast.Pos
+	var block func(bops []ast.BranchOrPlugin)
+	block = func(bops []ast.BranchOrPlugin) {
+		for _, bop := range bops {
+			switch node := bop.(type) {
+			case ast.Plugin:
+				positions = append(positions, node.Pos())
+			case ast.Branch:
+				positions = append(positions, node.IfBlock.Pos())
+				block(node.IfBlock.Block)
+				for _, elseIf := range node.ElseIfBlock {
+					positions = append(positions, elseIf.Pos())
+					block(elseIf.Block)
+				}
+				positions = append(positions, node.ElseBlock.Pos())
+				block(node.ElseBlock.Block)
+			}
+		}
+	}
+
+	for _, sections := range [][]ast.PluginSection{cfg.Input, cfg.Filter, cfg.Output} {
+		for _, section := range sections {
+			positions = append(positions, section.Pos())
+			block(section.BranchOrPlugins)
+		}
+	}
+
+	return positions
+}
+
+// compileErrorRe matches the position in the compile errors of Logstash,
+// e.g. "Expected one of [ \t\r\n], "#", "{" at line 12, column 5 (byte 230)
+// after filter {...", where the text after "after" is the beginning of the
+// Logstash config file up to the position of the error.
+var compileErrorRe = regexp.MustCompile(`at line (\d+), column (\d+) \(byte \d+\)`)
+
+// TranslateError translates the position in a compile error of Logstash
+// into the position in the original Logstash config file. pipelines
+// contains the Logstash config files of each pipeline as loaded by
+// Logstash, in the order in which Logstash concatenates them to the config
+// of the pipeline. If the position can not be translated, the message is
+// returned unchanged.
+func TranslateError(message string, pipelines [][]File) string {
+	m := compileErrorRe.FindStringSubmatchIndex(message)
+	if m == nil {
+		return message
+	}
+	line, _ := strconv.Atoi(message[m[2]:m[3]])
+	column, _ := strconv.Atoi(message[m[4]:m[5]])
+	after := strings.TrimPrefix(message[m[1]:], " after ")
+
+	// Logstash does not report the pipeline, find the pipeline by the config
+	// before the position of the error.
+	var found [][]File
+	for _, files := range pipelines {
+		if len(files) == 0 {
+			continue
+		}
+		if len(pipelines) == 1 || after != "" && bytes.HasPrefix(joinBodies(files), []byte(after)) {
+			found = append(found, files)
+		}
+	}
+	if len(found) != 1 {
+		return message
+	}
+
+	file, line, ok := fileOfLine(found[0], line)
+	if !ok {
+		return message
+	}
+	source, ok := file.Source(line, column)
+	if !ok {
+		return message
+	}
+
+	translated := fmt.Sprintf("at %s", source)
+	if file.SourceMap != nil {
+		translated = fmt.Sprintf("within %s (line %d, column %d of the rewritten config)", source, line, column)
+	}
+	return message[:m[0]] + translated + message[m[1]:]
+}
+
+// joinBodies returns the config of a pipeline, which consists of the given
+// files, the way Logstash concatenates the files.
+func joinBodies(files []File) []byte {
+	bodies := make([][]byte, 0, len(files))
+	for _, f := range files {
+		bodies = append(bodies, f.Body)
+	}
+	return bytes.Join(bodies, []byte("\n"))
+}
+
+// fileOfLine returns the file, the given line of the concatenated config
+// of a pipeline (see joinBodies) belongs to, together with the line within
+// this file.
+func fileOfLine(files []File, line int) (File, int, bool) {
+	start := 1
+	for _, f := range files {
+		lines := bytes.Count(f.Body, []byte("\n")) + 1
+		if line < start+lines {
+			return f, line - start + 1, line >= start
+		}
+		start += lines
+	}
+	return File{}, 0, false
+}
@@ -0,0 +1,167 @@
+package logstashconfig_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/logstashconfig"
+)
+
+const sourceMapConfig = `input { stdin { id => in } }
+filter {
+  if [type] == "web" {
+    drop { id => drop_it }
+  }
+}
+output { stdout { id => out } }
+`
+
+// lineOf returns the line number of the first line of body containing s.
+func lineOf(body []byte, s string) int {
+	for i, line := range strings.Split(string(body), "\n") {
+		if strings.Contains(line, s) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func TestSourceMap(t *testing.T) {
+	is := is.New(t)
+
+	f := logstashconfig.File{
+		Name: "/conf.d/main.conf",
+		Body: []byte(sourceMapConfig),
+	}
+
+	err := f.InstrumentDrops()
+	is.NoErr(err)
+	_, err = f.ReplaceOutputs()
+	is.NoErr(err)
+	is.True(f.SourceMap != nil) // rewritten config has a source map
+
+	cases := []struct {
+		name   string
+		line   string
+		want   logstashconfig.Position
+		wantOK bool
+	}{
+		{
+			name:   "plugin",
+			line:   "drop {",
+			want:   logstashconfig.Position{File: "conf.d/main.conf", Line: 4, Column: 5},
+			wantOK: true,
+		},
+		{
+			name:   "attribute within plugin",
+			line:   "id => drop_it",
+			want:   logstashconfig.Position{File: "conf.d/main.conf", Line: 4, Column: 5},
+			wantOK: true,
+		},
+		{
+			name:   "branch",
+			line:   `if [type] == "web"`,
+			want:   logstashconfig.Position{File: "conf.d/main.conf", Line: 3, Column: 3},
+			wantOK: true,
+		},
+		{
+			name:   "replaced plugin",
+			line:   "send_to",
+			want:   logstashconfig.Position{File: "conf.d/main.conf", Line: 7, Column: 10},
+			wantOK: true,
+		},
+		{
+			name: "added plugin",
+			line: "__lfv_drop_drop_it",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			line := lineOf(f.Body, test.line)
+			is.True(line > 0) // line found in rewritten config
+
+			got, ok := f.Source(line, 1)
+			is.Equal(ok, test.wantOK)
+			is.Equal(got, test.want)
+		})
+	}
+}
+
+func TestTranslateError(t *testing.T) {
+	rewritten := logstashconfig.File{
+		Name: "/conf.d/main.conf",
+		Body: []byte(sourceMapConfig),
+	}
+	err := rewritten.InstrumentDrops()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dropLine := lineOf(rewritten.Body, "drop {")
+
+	original := logstashconfig.File{
+		Name: "/conf.d/other.conf",
+		Body: []byte("filter {\n  mutate { id => x }\n}\n"),
+	}
+	second := logstashconfig.File{
+		Name: "/conf.d/second.conf",
+		Body: []byte("filter {\n  grok {}\n  bad\n}"),
+	}
+
+	cases := []struct {
+		name    string
+		message string
+		files   [][]logstashconfig.File
+
+		want string
+	}{
+		{
+			name:    "original config",
+			message: "Expected one of [ \\t\\r\\n], \"#\", \"{\" at line 2, column 3 (byte 12) after filter {\n  ",
+			files:   [][]logstashconfig.File{{rewritten}, {original}},
+
+			want: "Expected one of [ \\t\\r\\n], \"#\", \"{\" at conf.d/other.conf:2:3 after filter {\n  ",
+		},
+		{
+			name:    "rewritten config",
+			message: "Expected one of [ \\t\\r\\n], \"#\", \"{\" at line " + strconv.Itoa(dropLine) + ", column 9 (byte 300) after input {",
+			files:   [][]logstashconfig.File{{rewritten}, {original}},
+
+			want: "Expected one of [ \\t\\r\\n], \"#\", \"{\" within conf.d/main.conf:4:5 (line " + strconv.Itoa(dropLine) + ", column 9 of the rewritten config) after input {",
+		},
+		{
+			name:    "second file of pipeline",
+			message: "Expected one of [ \\t\\r\\n], \"#\", \"{\" at line 7, column 6 (byte 45) after filter {\n  mutate { id => x }\n}\n\nfilter {\n  grok {}\n  bad",
+			files:   [][]logstashconfig.File{{rewritten}, {original, second}},
+
+			want: "Expected one of [ \\t\\r\\n], \"#\", \"{\" at conf.d/second.conf:3:6 after filter {\n  mutate { id => x }\n}\n\nfilter {\n  grok {}\n  bad",
+		},
+		{
+			name:    "unknown file",
+			message: "Expected one of [ \\t\\r\\n] at line 2, column 3 (byte 12) after output {",
+			files:   [][]logstashconfig.File{{rewritten}, {original}},
+
+			want: "Expected one of [ \\t\\r\\n] at line 2, column 3 (byte 12) after output {",
+		},
+		{
+			name:    "no position",
+			message: "Couldn't find any filter plugin named 'foo'",
+			files:   [][]logstashconfig.File{{original}},
+
+			want: "Couldn't find any filter plugin named 'foo'",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			is.Equal(logstashconfig.TranslateError(test.message, test.files), test.want)
+		})
+	}
+}
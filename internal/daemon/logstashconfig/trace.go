@@ -0,0 +1,97 @@
+package logstashconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/breml/logstash-config/ast"
+	"github.com/breml/logstash-config/ast/astutil"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/coverage"
+)
+
+// TracePrefix is the prefix of the IDs of the plugins added by
+// InstrumentTrace.
+const TracePrefix = "__lfv_trace_"
+
+// traceInit flattens the fields of an event to a map with the field
+// references (e.g. [a][b]) as keys.
+const traceInit = `@lfv_flatten = lambda do |prefix, value, out|
+  if value.is_a?(Hash)
+    value.each { |k, v| @lfv_flatten.call(prefix + "[" + k.to_s + "]", v, out) }
+  else
+    out[prefix] = value
+  end
+  out
+end`
+
+// traceCode compares the fields of the event with the fields after the
+// previous traced plugin and appends the changes to
+// [@metadata][__lfv_trace].
+const traceCode = `data = event.to_hash
+data.delete("@metadata")
+current = @lfv_flatten.call("", data, {})
+previous = event.get("[@metadata][__lfv_trace_last]")
+if previous
+  set = {}
+  current.each { |k, v| set[k] = v unless previous.key?(k) && previous[k].class == v.class && previous[k].to_s == v.to_s }
+  removed = previous.keys - current.keys
+  unless set.empty? && removed.empty?
+    trace = event.get("[@metadata][__lfv_trace]") || []
+    trace << { "id" => "%s", "plugin" => "%s", "set" => set, "removed" => removed }
+    event.set("[@metadata][__lfv_trace]", trace)
+  end
+end
+event.set("[@metadata][__lfv_trace_last]", current)`
+
+// InstrumentTrace adds a ruby filter after each filter plugin, which
+// records the fields set and removed by the plugin in
+// [@metadata][__lfv_trace] of the event. An additional ruby filter at the
+// beginning of each filter section records the fields of the event before
+// the first plugin.
+func (f *File) InstrumentTrace() error {
+	err := f.parse()
+	if err != nil {
+		return err
+	}
+
+	for i := range f.config.Filter {
+		start := tracePlugin(injectedID(TracePrefix+"start_", fmt.Sprintf("%s_%d", f.Name, i)), "", "")
+		f.config.Filter[i].BranchOrPlugins = astutil.ApplyPlugins(f.config.Filter[i].BranchOrPlugins, traceWalk)
+		f.config.Filter[i].BranchOrPlugins = append([]ast.BranchOrPlugin{start}, f.config.Filter[i].BranchOrPlugins...)
+	}
+
+	f.serialize()
+
+	return nil
+}
+
+func traceWalk(c *astutil.Cursor) {
+	plugin := c.Plugin()
+	if plugin == nil {
+		return
+	}
+	id, err := plugin.ID()
+	if err != nil || strings.HasPrefix(id, coverage.MarkerPrefix) {
+		return
+	}
+
+	c.InsertAfter(tracePlugin(injectedID(TracePrefix, id), id, plugin.Name()))
+}
+
+// rubyStringEscaper escapes a value for a double quoted Ruby string within
+// a single quoted Logstash config string.
+var rubyStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `#`, `\#`, `'`, `\x27`)
+
+// tracePlugin returns the ruby filter, which records the changes of the
+// plugin with the given ID and name.
+func tracePlugin(id string, tracedID string, tracedName string) ast.Plugin {
+	code := fmt.Sprintf(traceCode, rubyStringEscaper.Replace(tracedID), rubyStringEscaper.Replace(tracedName))
+
+	return ast.NewPlugin("ruby",
+		ast.NewStringAttribute("id", id, ast.DoubleQuoted),
+		ast.NewStringAttribute("init", traceInit, ast.SingleQuoted),
+		ast.NewStringAttribute("code", code, ast.SingleQuoted),
+		ast.NewStringAttribute("tag_on_exception", TracePrefix+"exception", ast.DoubleQuoted),
+	)
+}
@@ -0,0 +1,33 @@
+package pipeline
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LoadError is returned, if Logstash failed to load pipelines, e.g.
+// because of a compile error in the Logstash config.
+type LoadError struct {
+	// Messages contains the error message of Logstash by pipeline ID. The
+	// pipeline ID is empty, if Logstash did not report it.
+	Messages map[string]string
+}
+
+func (e LoadError) Error() string {
+	ids := make([]string, 0, len(e.Messages))
+	for id := range e.Messages {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			parts = append(parts, e.Messages[id])
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("pipeline %q: %s", id, e.Messages[id]))
+	}
+	return "Logstash failed to load the pipelines: " + strings.Join(parts, "; ")
+}
@@ -3,6 +3,7 @@ package pipeline
 import (
 	"archive/zip"
 	"bytes"
+	"encoding/json"
 	"os"
 	"path"
 	"path/filepath"
@@ -12,14 +13,31 @@ import (
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/coverage"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/logstashconfig"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pluginmock"
 )
 
+// SourceMapFile is the name of the file in the zip archive, which contains
+// the source maps of the rewritten Logstash config files by their name.
+const SourceMapFile = "__lfv_sourcemap.json"
+
 type Archive struct {
 	Pipelines Pipelines
 	File      string
 	BasePath  string
+
+	// Coverage, if set, collects the coverage points of the Logstash
+	// config files, which are instrumented accordingly.
+	Coverage *coverage.Coverage
+
+	// Trace, if set, instruments the filters of the Logstash config files
+	// to record the changes of each filter to the events.
+	Trace bool
+
+	// Drops, if set, instruments the drop filters of the Logstash config
+	// files to log the events, which are dropped.
+	Drops bool
 }
 
 type Pipelines []Pipeline
@@ -73,7 +91,7 @@ func processNestedKeys(pipelines Pipelines) {
 	}
 }
 
-func (a Archive) ZipWithPreprocessor(addMissingID bool, preprocess func([]byte) ([]byte, error)) (data []byte, inputs map[string]int, err error) {
+func (a Archive) ZipWithPreprocessor(addMissingID bool, preprocess func(*logstashconfig.File) error) (data []byte, inputs map[string]int, err error) {
 	buf := new(bytes.Buffer)
 	w := zip.NewWriter(buf)
 
@@ -92,14 +110,8 @@ func (a Archive) ZipWithPreprocessor(addMissingID bool, preprocess func([]byte)
 
 	inputs = map[string]int{}
 	outputs := map[string]int{}
-	for _, pipeline := range a.Pipelines {
-		if strings.HasSuffix(pipeline.Config, "/") {
-			pipeline.Config += "*"
-		}
-		configFilepath := filepath.Join(a.BasePath, pipeline.Config)
-		if filepath.IsAbs(pipeline.Config) {
-			configFilepath = pipeline.Config
-		}
+	sourceMaps := map[string]logstashconfig.SourceMap{}
+	for _, configFilepath := range a.ConfigPatterns() {
 		files, err := doublestar.Glob(configFilepath)
 		if err != nil {
 			return nil, nil, err
@@ -133,21 +145,42 @@ func (a Archive) ZipWithPreprocessor(addMissingID bool, preprocess func([]byte)
 				return nil, nil, err
 			}
 
-			body, err = preprocess(body)
-			if err != nil {
-				return nil, nil, err
-			}
-
 			configFile := logstashconfig.File{
 				Name: relFile,
 				Body: body,
 			}
 
+			err = preprocess(&configFile)
+			if err != nil {
+				return nil, nil, err
+			}
+
 			in, out, err := configFile.Validate(addMissingID)
 			if err != nil {
 				return nil, nil, err
 			}
 
+			if a.Coverage != nil {
+				err = configFile.InstrumentCoverage(a.Coverage, body)
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+
+			if a.Trace {
+				err = configFile.InstrumentTrace()
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+
+			if a.Drops {
+				err = configFile.InstrumentDrops()
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+
 			for id, count := range in {
 				inputs[id] += count
 			}
@@ -159,6 +192,21 @@ func (a Archive) ZipWithPreprocessor(addMissingID bool, preprocess func([]byte)
 			if err != nil {
 				return nil, nil, err
 			}
+
+			if configFile.SourceMap != nil {
+				sourceMaps[relFile] = configFile.SourceMap
+			}
+		}
+	}
+
+	if len(sourceMaps) > 0 {
+		f, err := w.Create(SourceMapFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		err = json.NewEncoder(f).Encode(sourceMaps)
+		if err != nil {
+			return nil, nil, err
 		}
 	}
 
@@ -174,21 +222,29 @@ func (a Archive) ZipWithPreprocessor(addMissingID bool, preprocess func([]byte)
 	return buf.Bytes(), inputs, nil
 }
 
-func NoopPreprocessor(body []byte) ([]byte, error) {
-	return body, nil
-}
-
-func ApplyMocksPreprocessor(m pluginmock.Mocks) func(body []byte) ([]byte, error) {
-	return func(body []byte) ([]byte, error) {
-		configFile := logstashconfig.File{
-			Body: body,
+// ConfigPatterns returns for each pipeline the glob pattern of its
+// Logstash config files with relative paths resolved against the base path.
+func (a Archive) ConfigPatterns() []string {
+	patterns := make([]string, 0, len(a.Pipelines))
+	for _, pipeline := range a.Pipelines {
+		config := pipeline.Config
+		if strings.HasSuffix(config, "/") {
+			config += "*"
 		}
-
-		err := configFile.ApplyMocks(m)
-		if err != nil {
-			return body, err
+		if !filepath.IsAbs(config) {
+			config = filepath.Join(a.BasePath, config)
 		}
+		patterns = append(patterns, config)
+	}
+	return patterns
+}
+
+func NoopPreprocessor(configFile *logstashconfig.File) error {
+	return nil
+}
 
-		return configFile.Body, nil
+func ApplyMocksPreprocessor(m pluginmock.Mocks) func(configFile *logstashconfig.File) error {
+	return func(configFile *logstashconfig.File) error {
+		return configFile.ApplyMocks(m)
 	}
 }
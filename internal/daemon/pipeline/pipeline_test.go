@@ -3,12 +3,16 @@ package pipeline_test
 import (
 	"archive/zip"
 	"bytes"
+	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/matryer/is"
 	"gopkg.in/yaml.v2"
 
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/coverage"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/logstashconfig"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pipeline"
 )
 
@@ -163,3 +167,92 @@ func TestZip(t *testing.T) {
 		})
 	}
 }
+
+func TestZipCoverage(t *testing.T) {
+	is := is.New(t)
+
+	wd, err := os.Getwd()
+	is.NoErr(err)
+
+	a, err := pipeline.New("testdata/pipelines_basic.yml", filepath.Join(wd, "testdata"))
+	is.NoErr(err)
+
+	a.Coverage = coverage.New()
+	_, _, err = a.ZipWithPreprocessor(false, pipeline.NoopPreprocessor)
+	is.NoErr(err)
+
+	files := a.Coverage.Files()
+	is.Equal(len(files), 1)                     // one config file
+	is.Equal(files[0].Name, "folder/main.conf") // relative name of the config file
+	is.Equal(len(files[0].Points), 2)           // stdin and stdout plugin
+}
+
+func TestZipSourceMap(t *testing.T) {
+	is := is.New(t)
+
+	wd, err := os.Getwd()
+	is.NoErr(err)
+
+	a, err := pipeline.New("testdata/pipelines_basic.yml", filepath.Join(wd, "testdata"))
+	is.NoErr(err)
+
+	a.Trace = true
+	b, _, err := a.ZipWithPreprocessor(false, pipeline.NoopPreprocessor)
+	is.NoErr(err)
+
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	is.NoErr(err)
+
+	var sourceMaps map[string]logstashconfig.SourceMap
+	for _, f := range r.File {
+		if f.Name != pipeline.SourceMapFile {
+			continue
+		}
+		rc, err := f.Open()
+		is.NoErr(err)
+		err = json.NewDecoder(rc).Decode(&sourceMaps)
+		is.NoErr(err)
+		is.NoErr(rc.Close())
+	}
+
+	is.Equal(len(sourceMaps), 1) // source map of the rewritten config file
+	source, ok := sourceMaps["/folder/main.conf"].Lookup(1)
+	is.True(ok)                                       // input section is mapped
+	is.Equal(source.String(), "folder/main.conf:1:1") // position of the input section
+}
+
+func TestConfigPatterns(t *testing.T) {
+	cases := []struct {
+		name     string
+		pipeline string
+		basePath string
+
+		want []string
+	}{
+		{
+			name:     "glob",
+			pipeline: "testdata/pipelines_advanced.yml",
+			basePath: "/base",
+
+			want: []string{"/base/folder/**/*.conf"},
+		},
+		{
+			name:     "directory",
+			pipeline: "testdata/pipelines_basic_dir_name.yml",
+			basePath: "/base",
+
+			want: []string{"/base/folder/*"},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			a, err := pipeline.New(test.pipeline, test.basePath)
+			is.NoErr(err)
+
+			is.Equal(test.want, a.ConfigPatterns())
+		})
+	}
+}
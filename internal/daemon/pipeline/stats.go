@@ -0,0 +1,16 @@
+package pipeline
+
+import "time"
+
+// PluginStats contains the statistics of a single plugin of a pipeline as
+// reported by the Logstash node stats API.
+type PluginStats struct {
+	PipelineID string
+	ID         string
+	Name       string
+	// Kind is one of input, filter or output.
+	Kind      string
+	EventsIn  int64
+	EventsOut int64
+	Duration  time.Duration
+}
@@ -102,6 +102,9 @@ func (m Mocks) Walk(c *astutil.Cursor) {
 			return
 		}
 		replacement.Attributes = append(replacement.Attributes, ast.NewStringAttribute("id", id, ast.DoubleQuoted))
-		c.Replace(replacement)
+		// Keep the position of the mocked plugin for the source map.
+		plugin := *replacement
+		plugin.Start = c.Plugin().Start
+		c.Replace(plugin)
 	}
 }
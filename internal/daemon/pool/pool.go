@@ -13,14 +13,27 @@ import (
 )
 
 type LogstashController interface {
+	ID() string
+	State() string
 	SetupTest(pipelines pipeline.Pipelines) error
 	ExecuteTest(pipelines pipeline.Pipelines, expectedEvents int) error
 	GetResults() ([]string, error)
+	StreamResults(onEvent func(event string) error, onLog func(line string) error) error
+	ExecutionTimes() (reload time.Duration, processing time.Duration)
+	PluginStats(pipelineIDs ...string) ([]pipeline.PluginStats, error)
+	PipelinesConfig() ([]byte, error)
 	Teardown() error
 	IsHealthy() bool
 	Kill()
 }
 
+// ControllerStatus contains the state of a Logstash controller of the pool.
+type ControllerStatus struct {
+	ID       string
+	State    string
+	Assigned bool
+}
+
 type LogstashControllerFactory func() (LogstashController, error)
 
 type LogstashDetectVersion func() (semver.Version, error)
@@ -161,3 +174,30 @@ func (p *Pool) Return(instance LogstashController, clean bool) {
 	}
 	p.log.Warning("Instance not found in assigned controllers. Instance might have been cleaned up by housekeeping due to unhealthy state.")
 }
+
+// Status returns the state of all the Logstash controllers of the pool.
+func (p *Pool) Status() []ControllerStatus {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	status := make([]ControllerStatus, 0, len(p.availableControllers)+len(p.assignedControllers))
+	for _, controllers := range []struct {
+		controllers []LogstashController
+		assigned    bool
+	}{
+		{p.assignedControllers, true},
+		{p.availableControllers, false},
+	} {
+		for _, controller := range controllers.controllers {
+			if controller == nil {
+				continue
+			}
+			status = append(status, ControllerStatus{
+				ID:       controller.ID(),
+				State:    controller.State(),
+				Assigned: controllers.assigned,
+			})
+		}
+	}
+	return status
+}
@@ -1,6 +1,7 @@
 package session
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -20,17 +21,28 @@ type Controller struct {
 	sessions map[string]*Session
 	finished bool
 
+	// reserved is the number of slots taken by sessions, which are
+	// currently set up or torn down and therefore not part of sessions.
+	reserved int
+
 	tempdir                    string
 	logstashPool               Pool
+	maxSessions                int
 	noCleanup                  bool
 	isOrderedPipelineSupported bool
 	log                        logging.Logger
 }
 
-// NewController creates a new session Controller.
-func NewController(tempdir string, logstashPool Pool, noCleanup bool, isOrderedPipelineSupported bool, log logging.Logger) *Controller {
+// NewController creates a new session Controller, which allows at most
+// maxSessions concurrent sessions (at least 1), which should match the
+// size of the Logstash pool.
+func NewController(tempdir string, logstashPool Pool, maxSessions int, noCleanup bool, isOrderedPipelineSupported bool, log logging.Logger) *Controller {
 	mu := &sync.Mutex{}
 
+	if maxSessions < 1 {
+		maxSessions = 1
+	}
+
 	return &Controller{
 		mutex: mu,
 		wg:    &sync.WaitGroup{},
@@ -40,6 +52,7 @@ func NewController(tempdir string, logstashPool Pool, noCleanup bool, isOrderedP
 
 		tempdir:                    tempdir,
 		logstashPool:               logstashPool,
+		maxSessions:                maxSessions,
 		noCleanup:                  noCleanup,
 		isOrderedPipelineSupported: isOrderedPipelineSupported,
 		log:                        log,
@@ -47,33 +60,40 @@ func NewController(tempdir string, logstashPool Pool, noCleanup bool, isOrderedP
 }
 
 // Create creates a new Session.
+//
+// The mutex is only held while a slot for the new session is reserved and
+// while the session is registered, such that the potentially slow start
+// and setup of Logstash does not block the other sessions.
 func (s *Controller) Create(pipelines pipeline.Pipelines, configFiles []logstashconfig.File) (*Session, error) {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-
-	// TODO: Link this value to pool size
-	for len(s.sessions) > 1 {
-		s.cond.Wait()
-		if s.finished {
-			return nil, errors.New("shutdown in progress")
-		}
+	err := s.reserve()
+	if err != nil {
+		return nil, err
 	}
 
 	logstashController, err := s.logstashPool.Get()
 	if err != nil {
+		s.release()
 		return nil, err
 	}
 
 	session := newSession(s.tempdir, logstashController, s.noCleanup, s.isOrderedPipelineSupported, s.log)
-	s.sessions[session.ID()] = session
-
-	s.wg.Add(1)
 
 	err = session.setupTest(pipelines, configFiles)
 	if err != nil {
+		// The client does not know the session, so it is destroyed right
+		// away.
+		if destroyErr := s.teardown(session); destroyErr != nil {
+			s.log.Warningf("session %s: %v", session.ID(), destroyErr)
+		}
+		s.release()
 		return nil, err
 	}
 
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.reserved--
+	s.sessions[session.ID()] = session
+
 	return session, nil
 }
 
@@ -91,17 +111,52 @@ func (s *Controller) Get(id string) (*Session, error) {
 // Destroy deletes an existing session.
 func (s *Controller) DestroyByID(id string) error {
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
 	session, ok := s.sessions[id]
 	if !ok {
+		s.mutex.Unlock()
 		return errors.Errorf("no valid session found for id %q", id)
 	}
-	defer func() {
-		delete(s.sessions, id)
-		s.cond.Signal()
-		s.wg.Done()
-	}()
+	// The slot of the session stays reserved until its Logstash controller
+	// is returned to the pool.
+	delete(s.sessions, id)
+	s.reserved++
+	s.mutex.Unlock()
 
+	defer s.release()
+	return s.teardown(session)
+}
+
+// reserve waits for a free slot and reserves it for a new session.
+func (s *Controller) reserve() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for len(s.sessions)+s.reserved >= s.maxSessions {
+		s.cond.Wait()
+		if s.finished {
+			return errors.New("shutdown in progress")
+		}
+	}
+
+	s.reserved++
+	s.wg.Add(1)
+
+	return nil
+}
+
+// release frees a slot, which has been reserved by reserve.
+func (s *Controller) release() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.reserved--
+	s.cond.Signal()
+	s.wg.Done()
+}
+
+// teardown tears down the session and returns its Logstash controller to
+// the pool.
+func (s *Controller) teardown(session *Session) error {
 	err := session.teardown()
 	if err != nil {
 		s.logstashPool.Return(session.logstashController, false)
@@ -112,6 +167,27 @@ func (s *Controller) DestroyByID(id string) error {
 	return nil
 }
 
+// Sessions returns information about all the active sessions ordered by
+// their creation time. Sessions, which are currently set up or torn down,
+// are not included.
+func (s *Controller) Sessions() []Info {
+	s.mutex.Lock()
+	sessions := make([]*Session, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	s.mutex.Unlock()
+
+	infos := make([]Info, 0, len(sessions))
+	for _, session := range sessions {
+		infos = append(infos, session.Info())
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Created.Before(infos[j].Created)
+	})
+	return infos
+}
+
 // WaitFinish waits for all currently running sessions to finish.
 func (s *Controller) WaitFinish() chan struct{} {
 	s.mutex.Lock()
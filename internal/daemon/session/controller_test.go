@@ -47,13 +47,16 @@ func TestSession(t *testing.T) {
 						GetResultsFunc: func() ([]string, error) {
 							return []string{"some_random_result"}, nil
 						},
+						ExecutionTimesFunc: func() (time.Duration, time.Duration) {
+							return 0, 0
+						},
 					}
 					return logstashController, nil
 				},
 				ReturnFunc: func(instance pool.LogstashController, clean bool) {},
 			}
 
-			c := session.NewController(tempdir, pool, false, true, logging.NoopLogger)
+			c := session.NewController(tempdir, pool, 2, false, true, logging.NoopLogger)
 
 			pipelines := pipeline.Pipelines{
 				pipeline.Pipeline{
@@ -143,7 +146,7 @@ func TestCreate(t *testing.T) {
 				ReturnFunc: func(instance pool.LogstashController, clean bool) {},
 			}
 
-			c := session.NewController(tempdir, pool, false, true, logging.NoopLogger)
+			c := session.NewController(tempdir, pool, 2, false, true, logging.NoopLogger)
 
 			pipelines := pipeline.Pipelines{
 				pipeline.Pipeline{
@@ -177,3 +180,258 @@ func TestCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateMaxSessions(t *testing.T) {
+	is := is.New(t)
+
+	tempdir := t.TempDir()
+
+	pool := &PoolMock{
+		GetFunc: func() (pool.LogstashController, error) {
+			logstashController := &LogstashControllerMock{
+				SetupTestFunc: func(pipelines pipeline.Pipelines) error {
+					return nil
+				},
+				TeardownFunc: func() error {
+					return nil
+				},
+			}
+			return logstashController, nil
+		},
+		ReturnFunc: func(instance pool.LogstashController, clean bool) {},
+	}
+
+	c := session.NewController(tempdir, pool, 1, false, true, logging.NoopLogger)
+
+	pipelines := pipeline.Pipelines{
+		pipeline.Pipeline{
+			ID:      "main",
+			Config:  "main.conf",
+			Workers: 1,
+		},
+	}
+
+	configFiles := []logstashconfig.File{
+		{
+			Name: "main.conf",
+			Body: []byte(`input { stdin{ id => testid } } output { stdout{} }`),
+		},
+	}
+
+	s, err := c.Create(pipelines, configFiles)
+	is.NoErr(err)
+
+	created := make(chan *session.Session)
+	go func() {
+		s2, err := c.Create(pipelines, configFiles)
+		is.NoErr(err)
+		created <- s2
+	}()
+
+	select {
+	case <-created:
+		t.Fatal("second session created while the maximum number of sessions is reached")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	err = c.DestroyByID(s.ID())
+	is.NoErr(err)
+
+	s2 := <-created
+	is.True(s.ID() != s2.ID()) // IDs of two separate sessions are not equal
+}
+
+func TestCreateFailure(t *testing.T) {
+	is := is.New(t)
+
+	tempdir := t.TempDir()
+
+	returned := 0
+	pool := &PoolMock{
+		GetFunc: func() (pool.LogstashController, error) {
+			logstashController := &LogstashControllerMock{
+				SetupTestFunc: func(pipelines pipeline.Pipelines) error {
+					return nil
+				},
+				TeardownFunc: func() error {
+					return nil
+				},
+			}
+			return logstashController, nil
+		},
+		ReturnFunc: func(instance pool.LogstashController, clean bool) {
+			returned++
+		},
+	}
+
+	c := session.NewController(tempdir, pool, 1, false, true, logging.NoopLogger)
+
+	pipelines := pipeline.Pipelines{
+		pipeline.Pipeline{
+			ID:      "main",
+			Config:  "main.conf",
+			Workers: 1,
+		},
+	}
+
+	_, err := c.Create(pipelines, []logstashconfig.File{
+		{
+			Name: "main.conf",
+			Body: []byte(`input { stdin{ id => testid }`),
+		},
+	})
+	is.True(err != nil)            // invalid config
+	is.Equal(len(c.Sessions()), 0) // failed session is destroyed
+	is.Equal(returned, 1)          // Logstash controller is returned to the pool
+
+	s, err := c.Create(pipelines, []logstashconfig.File{
+		{
+			Name: "main.conf",
+			Body: []byte(`input { stdin{ id => testid } } output { stdout{} }`),
+		},
+	})
+	is.NoErr(err) // failed session does not count towards the maximum number of sessions
+
+	err = c.DestroyByID(s.ID())
+	is.NoErr(err)
+}
+
+func TestSessions(t *testing.T) {
+	is := is.New(t)
+
+	tempdir := t.TempDir()
+
+	pool := &PoolMock{
+		GetFunc: func() (pool.LogstashController, error) {
+			logstashController := &LogstashControllerMock{
+				IDFunc: func() string {
+					return "instance"
+				},
+				SetupTestFunc: func(pipelines pipeline.Pipelines) error {
+					return nil
+				},
+				TeardownFunc: func() error {
+					return nil
+				},
+				ExecuteTestFunc: func(pipelines pipeline.Pipelines, expectedEvents int) error {
+					return nil
+				},
+			}
+			return logstashController, nil
+		},
+		ReturnFunc: func(instance pool.LogstashController, clean bool) {},
+	}
+
+	c := session.NewController(tempdir, pool, 2, false, true, logging.NoopLogger)
+
+	pipelines := pipeline.Pipelines{
+		pipeline.Pipeline{
+			ID:      "main",
+			Config:  "main.conf",
+			Workers: 1,
+		},
+	}
+
+	configFiles := []logstashconfig.File{
+		{
+			Name: "main.conf",
+			Body: []byte(`input { stdin{ id => testid } } output { stdout{} }`),
+		},
+	}
+
+	is.Equal(len(c.Sessions()), 0) // no sessions
+
+	s1, err := c.Create(pipelines, configFiles)
+	is.NoErr(err)
+	s2, err := c.Create(pipelines, configFiles)
+	is.NoErr(err)
+
+	err = s2.ExecuteTest("testid", []string{"a"}, nil, 1)
+	is.NoErr(err)
+
+	sessions := c.Sessions()
+	is.Equal(len(sessions), 2)                             // two sessions
+	is.Equal(sessions[0].ID, s1.ID())                      // sessions ordered by creation
+	is.Equal(sessions[1].ID, s2.ID())                      // sessions ordered by creation
+	is.Equal(sessions[0].TestExecutions, 0)                // no test executed in first session
+	is.Equal(sessions[1].TestExecutions, 1)                // one test executed in second session
+	is.Equal(sessions[1].LogstashControllerID, "instance") // Logstash instance of second session
+
+	err = c.DestroyByID(s1.ID())
+	is.NoErr(err)
+	is.Equal(len(c.Sessions()), 1) // one session left
+}
+
+func TestCreateDoesNotBlock(t *testing.T) {
+	is := is.New(t)
+
+	tempdir := t.TempDir()
+
+	booting := make(chan struct{})
+	boot := make(chan struct{})
+	pool := &PoolMock{
+		GetFunc: func() (pool.LogstashController, error) {
+			close(booting)
+			<-boot
+			logstashController := &LogstashControllerMock{
+				SetupTestFunc: func(pipelines pipeline.Pipelines) error {
+					return nil
+				},
+				TeardownFunc: func() error {
+					return nil
+				},
+			}
+			return logstashController, nil
+		},
+		ReturnFunc: func(instance pool.LogstashController, clean bool) {},
+	}
+
+	c := session.NewController(tempdir, pool, 1, false, true, logging.NoopLogger)
+
+	pipelines := pipeline.Pipelines{
+		pipeline.Pipeline{
+			ID:      "main",
+			Config:  "main.conf",
+			Workers: 1,
+		},
+	}
+
+	configFiles := []logstashconfig.File{
+		{
+			Name: "main.conf",
+			Body: []byte(`input { stdin{ id => testid } } output { stdout{} }`),
+		},
+	}
+
+	created := make(chan *session.Session)
+	go func() {
+		s, err := c.Create(pipelines, configFiles)
+		is.NoErr(err)
+		created <- s
+	}()
+
+	<-booting
+
+	listed := make(chan []session.Info)
+	go func() {
+		listed <- c.Sessions()
+	}()
+
+	select {
+	case sessions := <-listed:
+		is.Equal(len(sessions), 0) // session in setup is not listed
+	case <-time.After(time.Second):
+		t.Fatal("listing the sessions is blocked by the creation of a session")
+	}
+
+	close(boot)
+
+	s := <-created
+	_, err := c.Get(s.ID())
+	is.NoErr(err) // created session is registered
+
+	err = c.DestroyByID(s.ID())
+	is.NoErr(err)
+
+	<-c.WaitFinish()
+}
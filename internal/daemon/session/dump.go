@@ -0,0 +1,70 @@
+package session
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// DumpConfig returns the Logstash configuration loaded for the session as
+// zip archive. The archive contains the files of the session directory,
+// that is the Logstash config files under test after the replacement of
+// the inputs and outputs (sut), the generated output pipelines
+// (lfv_outputs) and the input pipeline and fields of each test execution
+// (lfv_inputs/<n>), together with the pipelines.yml last loaded by
+// Logstash. The paths within the files refer to the file system of the
+// daemon.
+func (s *Session) DumpConfig() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+
+	err := filepath.WalkDir(s.sessionDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(s.sessionDir, path)
+		if err != nil {
+			return err
+		}
+
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		return writeZipFile(w, filepath.ToSlash(rel), body)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dump the session directory")
+	}
+
+	pipelines, err := s.logstashController.PipelinesConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dump pipelines.yml")
+	}
+	err = writeZipFile(w, "pipelines.yml", pipelines)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeZipFile(w *zip.Writer, name string, body []byte) error {
+	f, err := w.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(body)
+	return err
+}
@@ -72,3 +72,70 @@ output {
   }
 }
 `
+
+const inputBatchGenerator = `
+input {
+{{- range .Inputs }}
+  generator {
+    lines => [
+      {{ .InputLines }}
+    ]
+    {{ .InputCodec }}
+    count => 1
+    threads => 1
+    add_field => {
+      "[@metadata][__lfv_set]" => "{{ .Index }}"
+      "[@metadata][__lfv_offset]" => "{{ .Offset }}"
+    }
+  }
+{{- end }}
+}
+
+filter {
+  # The ids are unique over all the test case sets of the batch, the ids of
+  # each test case set start at the offset of the test case set.
+  ruby {
+    id => '__lfv_ruby_count'
+    init => '@count = Hash.new(0)'
+    code => 'set = event.get("[@metadata][__lfv_set]")
+             event.set("[@metadata][__lfv_id]", (event.get("[@metadata][__lfv_offset]").to_i + @count[set]).to_s)
+             @count[set] += 1'
+    tag_on_exception => '__lfv_ruby_count_exception'
+  }
+
+  mutate {
+    # Remove fields "host", "sequence" and optionally "message", which are
+    # automatically created by the generator input.
+    remove_field => [ "host", "sequence", "[@metadata][__lfv_offset]" ]
+  }
+
+  translate {
+    dictionary_path => "{{ .FieldsFilename }}"
+    field => "[@metadata][__lfv_id]"
+    destination => "[@metadata][__lfv_fields]"
+    exact => true
+    override => true
+    fallback => "__lfv_fields_not_found"
+    refresh_interval => 0
+  }
+
+  ruby {
+    id => '__lfv_ruby_fields'
+    code => 'fields = event.get("[@metadata][__lfv_fields]")
+             fields.each { |key, value| event.set(key, value) } unless fields == "__lfv_fields_not_found"
+             event.tag("lfv_fields_not_found") if fields == "__lfv_fields_not_found"
+             event.remove("[message]") if event.get("[message]") == "{{ .DummyEventInputIndicator }}"'
+    tag_on_exception => '__lfv_ruby_fields_exception'
+  }
+}
+
+output {
+{{- range $i, $input := .Inputs }}
+  {{ if $i }}else {{ end }}if [@metadata][__lfv_set] == "{{ $input.Index }}" {
+    pipeline {
+      send_to => [ "{{ $input.InputPluginName }}" ]
+    }
+  }
+{{- end }}
+}
+`
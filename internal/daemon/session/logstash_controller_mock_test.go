@@ -5,6 +5,7 @@ package session_test
 
 import (
 	"sync"
+	"time"
 
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pipeline"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/session"
@@ -23,18 +24,36 @@ var _ session.LogstashController = &LogstashControllerMock{}
 //			ExecuteTestFunc: func(pipelines pipeline.Pipelines, expectedEvents int) error {
 //				panic("mock out the ExecuteTest method")
 //			},
+//			ExecutionTimesFunc: func() (time.Duration, time.Duration) {
+//				panic("mock out the ExecutionTimes method")
+//			},
 //			GetResultsFunc: func() ([]string, error) {
 //				panic("mock out the GetResults method")
 //			},
+//			IDFunc: func() string {
+//				panic("mock out the ID method")
+//			},
 //			IsHealthyFunc: func() bool {
 //				panic("mock out the IsHealthy method")
 //			},
 //			KillFunc: func()  {
 //				panic("mock out the Kill method")
 //			},
+//			PipelinesConfigFunc: func() ([]byte, error) {
+//				panic("mock out the PipelinesConfig method")
+//			},
+//			PluginStatsFunc: func(pipelineIDs ...string) ([]pipeline.PluginStats, error) {
+//				panic("mock out the PluginStats method")
+//			},
 //			SetupTestFunc: func(pipelines pipeline.Pipelines) error {
 //				panic("mock out the SetupTest method")
 //			},
+//			StateFunc: func() string {
+//				panic("mock out the State method")
+//			},
+//			StreamResultsFunc: func(onEvent func(event string) error, onLog func(line string) error) error {
+//				panic("mock out the StreamResults method")
+//			},
 //			TeardownFunc: func() error {
 //				panic("mock out the Teardown method")
 //			},
@@ -48,18 +67,36 @@ type LogstashControllerMock struct {
 	// ExecuteTestFunc mocks the ExecuteTest method.
 	ExecuteTestFunc func(pipelines pipeline.Pipelines, expectedEvents int) error
 
+	// ExecutionTimesFunc mocks the ExecutionTimes method.
+	ExecutionTimesFunc func() (time.Duration, time.Duration)
+
 	// GetResultsFunc mocks the GetResults method.
 	GetResultsFunc func() ([]string, error)
 
+	// IDFunc mocks the ID method.
+	IDFunc func() string
+
 	// IsHealthyFunc mocks the IsHealthy method.
 	IsHealthyFunc func() bool
 
 	// KillFunc mocks the Kill method.
 	KillFunc func()
 
+	// PipelinesConfigFunc mocks the PipelinesConfig method.
+	PipelinesConfigFunc func() ([]byte, error)
+
+	// PluginStatsFunc mocks the PluginStats method.
+	PluginStatsFunc func(pipelineIDs ...string) ([]pipeline.PluginStats, error)
+
 	// SetupTestFunc mocks the SetupTest method.
 	SetupTestFunc func(pipelines pipeline.Pipelines) error
 
+	// StateFunc mocks the State method.
+	StateFunc func() string
+
+	// StreamResultsFunc mocks the StreamResults method.
+	StreamResultsFunc func(onEvent func(event string) error, onLog func(line string) error) error
+
 	// TeardownFunc mocks the Teardown method.
 	TeardownFunc func() error
 
@@ -72,30 +109,60 @@ type LogstashControllerMock struct {
 			// ExpectedEvents is the expectedEvents argument value.
 			ExpectedEvents int
 		}
+		// ExecutionTimes holds details about calls to the ExecutionTimes method.
+		ExecutionTimes []struct {
+		}
 		// GetResults holds details about calls to the GetResults method.
 		GetResults []struct {
 		}
+		// ID holds details about calls to the ID method.
+		ID []struct {
+		}
 		// IsHealthy holds details about calls to the IsHealthy method.
 		IsHealthy []struct {
 		}
 		// Kill holds details about calls to the Kill method.
 		Kill []struct {
 		}
+		// PipelinesConfig holds details about calls to the PipelinesConfig method.
+		PipelinesConfig []struct {
+		}
+		// PluginStats holds details about calls to the PluginStats method.
+		PluginStats []struct {
+			// PipelineIDs is the pipelineIDs argument value.
+			PipelineIDs []string
+		}
 		// SetupTest holds details about calls to the SetupTest method.
 		SetupTest []struct {
 			// Pipelines is the pipelines argument value.
 			Pipelines pipeline.Pipelines
 		}
+		// State holds details about calls to the State method.
+		State []struct {
+		}
+		// StreamResults holds details about calls to the StreamResults method.
+		StreamResults []struct {
+			// OnEvent is the onEvent argument value.
+			OnEvent func(event string) error
+			// OnLog is the onLog argument value.
+			OnLog func(line string) error
+		}
 		// Teardown holds details about calls to the Teardown method.
 		Teardown []struct {
 		}
 	}
-	lockExecuteTest sync.RWMutex
-	lockGetResults  sync.RWMutex
-	lockIsHealthy   sync.RWMutex
-	lockKill        sync.RWMutex
-	lockSetupTest   sync.RWMutex
-	lockTeardown    sync.RWMutex
+	lockExecuteTest     sync.RWMutex
+	lockExecutionTimes  sync.RWMutex
+	lockGetResults      sync.RWMutex
+	lockID              sync.RWMutex
+	lockIsHealthy       sync.RWMutex
+	lockKill            sync.RWMutex
+	lockPipelinesConfig sync.RWMutex
+	lockPluginStats     sync.RWMutex
+	lockSetupTest       sync.RWMutex
+	lockState           sync.RWMutex
+	lockStreamResults   sync.RWMutex
+	lockTeardown        sync.RWMutex
 }
 
 // ExecuteTest calls ExecuteTestFunc.
@@ -134,6 +201,33 @@ func (mock *LogstashControllerMock) ExecuteTestCalls() []struct {
 	return calls
 }
 
+// ExecutionTimes calls ExecutionTimesFunc.
+func (mock *LogstashControllerMock) ExecutionTimes() (time.Duration, time.Duration) {
+	if mock.ExecutionTimesFunc == nil {
+		panic("LogstashControllerMock.ExecutionTimesFunc: method is nil but LogstashController.ExecutionTimes was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockExecutionTimes.Lock()
+	mock.calls.ExecutionTimes = append(mock.calls.ExecutionTimes, callInfo)
+	mock.lockExecutionTimes.Unlock()
+	return mock.ExecutionTimesFunc()
+}
+
+// ExecutionTimesCalls gets all the calls that were made to ExecutionTimes.
+// Check the length with:
+//
+//	len(mockedLogstashController.ExecutionTimesCalls())
+func (mock *LogstashControllerMock) ExecutionTimesCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockExecutionTimes.RLock()
+	calls = mock.calls.ExecutionTimes
+	mock.lockExecutionTimes.RUnlock()
+	return calls
+}
+
 // GetResults calls GetResultsFunc.
 func (mock *LogstashControllerMock) GetResults() ([]string, error) {
 	if mock.GetResultsFunc == nil {
@@ -161,6 +255,33 @@ func (mock *LogstashControllerMock) GetResultsCalls() []struct {
 	return calls
 }
 
+// ID calls IDFunc.
+func (mock *LogstashControllerMock) ID() string {
+	if mock.IDFunc == nil {
+		panic("LogstashControllerMock.IDFunc: method is nil but LogstashController.ID was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockID.Lock()
+	mock.calls.ID = append(mock.calls.ID, callInfo)
+	mock.lockID.Unlock()
+	return mock.IDFunc()
+}
+
+// IDCalls gets all the calls that were made to ID.
+// Check the length with:
+//
+//	len(mockedLogstashController.IDCalls())
+func (mock *LogstashControllerMock) IDCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockID.RLock()
+	calls = mock.calls.ID
+	mock.lockID.RUnlock()
+	return calls
+}
+
 // IsHealthy calls IsHealthyFunc.
 func (mock *LogstashControllerMock) IsHealthy() bool {
 	if mock.IsHealthyFunc == nil {
@@ -215,6 +336,65 @@ func (mock *LogstashControllerMock) KillCalls() []struct {
 	return calls
 }
 
+// PipelinesConfig calls PipelinesConfigFunc.
+func (mock *LogstashControllerMock) PipelinesConfig() ([]byte, error) {
+	if mock.PipelinesConfigFunc == nil {
+		panic("LogstashControllerMock.PipelinesConfigFunc: method is nil but LogstashController.PipelinesConfig was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockPipelinesConfig.Lock()
+	mock.calls.PipelinesConfig = append(mock.calls.PipelinesConfig, callInfo)
+	mock.lockPipelinesConfig.Unlock()
+	return mock.PipelinesConfigFunc()
+}
+
+// PipelinesConfigCalls gets all the calls that were made to PipelinesConfig.
+// Check the length with:
+//
+//	len(mockedLogstashController.PipelinesConfigCalls())
+func (mock *LogstashControllerMock) PipelinesConfigCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockPipelinesConfig.RLock()
+	calls = mock.calls.PipelinesConfig
+	mock.lockPipelinesConfig.RUnlock()
+	return calls
+}
+
+// PluginStats calls PluginStatsFunc.
+func (mock *LogstashControllerMock) PluginStats(pipelineIDs ...string) ([]pipeline.PluginStats, error) {
+	if mock.PluginStatsFunc == nil {
+		panic("LogstashControllerMock.PluginStatsFunc: method is nil but LogstashController.PluginStats was just called")
+	}
+	callInfo := struct {
+		PipelineIDs []string
+	}{
+		PipelineIDs: pipelineIDs,
+	}
+	mock.lockPluginStats.Lock()
+	mock.calls.PluginStats = append(mock.calls.PluginStats, callInfo)
+	mock.lockPluginStats.Unlock()
+	return mock.PluginStatsFunc(pipelineIDs...)
+}
+
+// PluginStatsCalls gets all the calls that were made to PluginStats.
+// Check the length with:
+//
+//	len(mockedLogstashController.PluginStatsCalls())
+func (mock *LogstashControllerMock) PluginStatsCalls() []struct {
+	PipelineIDs []string
+} {
+	var calls []struct {
+		PipelineIDs []string
+	}
+	mock.lockPluginStats.RLock()
+	calls = mock.calls.PluginStats
+	mock.lockPluginStats.RUnlock()
+	return calls
+}
+
 // SetupTest calls SetupTestFunc.
 func (mock *LogstashControllerMock) SetupTest(pipelines pipeline.Pipelines) error {
 	if mock.SetupTestFunc == nil {
@@ -247,6 +427,69 @@ func (mock *LogstashControllerMock) SetupTestCalls() []struct {
 	return calls
 }
 
+// State calls StateFunc.
+func (mock *LogstashControllerMock) State() string {
+	if mock.StateFunc == nil {
+		panic("LogstashControllerMock.StateFunc: method is nil but LogstashController.State was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockState.Lock()
+	mock.calls.State = append(mock.calls.State, callInfo)
+	mock.lockState.Unlock()
+	return mock.StateFunc()
+}
+
+// StateCalls gets all the calls that were made to State.
+// Check the length with:
+//
+//	len(mockedLogstashController.StateCalls())
+func (mock *LogstashControllerMock) StateCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockState.RLock()
+	calls = mock.calls.State
+	mock.lockState.RUnlock()
+	return calls
+}
+
+// StreamResults calls StreamResultsFunc.
+func (mock *LogstashControllerMock) StreamResults(onEvent func(event string) error, onLog func(line string) error) error {
+	if mock.StreamResultsFunc == nil {
+		panic("LogstashControllerMock.StreamResultsFunc: method is nil but LogstashController.StreamResults was just called")
+	}
+	callInfo := struct {
+		OnEvent func(event string) error
+		OnLog   func(line string) error
+	}{
+		OnEvent: onEvent,
+		OnLog:   onLog,
+	}
+	mock.lockStreamResults.Lock()
+	mock.calls.StreamResults = append(mock.calls.StreamResults, callInfo)
+	mock.lockStreamResults.Unlock()
+	return mock.StreamResultsFunc(onEvent, onLog)
+}
+
+// StreamResultsCalls gets all the calls that were made to StreamResults.
+// Check the length with:
+//
+//	len(mockedLogstashController.StreamResultsCalls())
+func (mock *LogstashControllerMock) StreamResultsCalls() []struct {
+	OnEvent func(event string) error
+	OnLog   func(line string) error
+} {
+	var calls []struct {
+		OnEvent func(event string) error
+		OnLog   func(line string) error
+	}
+	mock.lockStreamResults.RLock()
+	calls = mock.calls.StreamResults
+	mock.lockStreamResults.RUnlock()
+	return calls
+}
+
 // Teardown calls TeardownFunc.
 func (mock *LogstashControllerMock) Teardown() error {
 	if mock.TeardownFunc == nil {
@@ -5,12 +5,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/bmatcuk/doublestar/v2"
 	"github.com/breml/logstash-config/ast"
 	"github.com/breml/logstash-config/ast/astutil"
 	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
 
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/idgen"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/logstashconfig"
@@ -32,10 +38,27 @@ type Session struct {
 
 	pipelines         pipeline.Pipelines
 	inputPluginCodecs map[string]string
-	testexec          int
+	testexec          atomic.Int32
+	created           time.Time
+
+	// pipelineFiles are the Logstash config files of each pipeline under
+	// test as loaded by Logstash, which are required to translate the
+	// positions in the errors of Logstash. The key is the ID of the pipeline
+	// in Logstash.
+	pipelineFiles map[string][]logstashconfig.File
+
+	// Statistics of the test executions, the number of input lines of the
+	// currently running test execution is kept in pendingEventsIn until its
+	// results are received.
+	pendingEventsIn int
+	executions      []ExecutionStats
 
 	noCleanup bool
 
+	// keepOnFailure keeps the session after a failed test execution until
+	// it is torn down by the client.
+	keepOnFailure atomic.Bool
+
 	log logging.Logger
 }
 
@@ -50,15 +73,47 @@ func newSession(baseDir string, logstashController pool.LogstashController, noCl
 		isOrderedPipelineSupported: isOrderedPipelineSupported,
 		noCleanup:                  noCleanup,
 		inputPluginCodecs:          map[string]string{},
+		pipelineFiles:              map[string][]logstashconfig.File{},
+		created:                    time.Now(),
 		log:                        log,
 	}
 }
 
 // ID returns the id of the session.
-func (s Session) ID() string {
+func (s *Session) ID() string {
 	return s.id
 }
 
+// SetKeepOnFailure sets, whether the session is kept after a failed test
+// execution, e.g. to dump its Logstash configuration.
+func (s *Session) SetKeepOnFailure(keep bool) {
+	s.keepOnFailure.Store(keep)
+}
+
+// KeepOnFailure returns true, if the session is kept after a failed test
+// execution until it is torn down by the client.
+func (s *Session) KeepOnFailure() bool {
+	return s.keepOnFailure.Load()
+}
+
+// Info contains information about a session for introspection.
+type Info struct {
+	ID                   string
+	Created              time.Time
+	TestExecutions       int
+	LogstashControllerID string
+}
+
+// Info returns information about the session.
+func (s *Session) Info() Info {
+	return Info{
+		ID:                   s.id,
+		Created:              s.created,
+		TestExecutions:       int(s.testexec.Load()),
+		LogstashControllerID: s.logstashController.ID(),
+	}
+}
+
 // setupTest prepares the Logstash configuration for a new test run.
 func (s *Session) setupTest(pipelines pipeline.Pipelines, configFiles []logstashconfig.File) error {
 	err := os.MkdirAll(s.sessionDir, 0700)
@@ -69,9 +124,16 @@ func (s *Session) setupTest(pipelines pipeline.Pipelines, configFiles []logstash
 	sutConfigDir := filepath.Join(s.sessionDir, "sut")
 
 	// adjust pipeline names and config directories to session
+	configPatterns := make(map[string]string, len(pipelines))
 	for i := range pipelines {
 		pipelineName := fmt.Sprintf("lfv_%s_%s", s.id, pipelines[i].ID)
 
+		config := pipelines[i].Config
+		if strings.HasSuffix(config, "/") {
+			config += "*"
+		}
+		configPatterns[pipelineName] = filepath.Join(sutConfigDir, config)
+
 		pipelines[i].ID = pipelineName
 		pipelines[i].Config = filepath.Join(sutConfigDir, pipelines[i].Config)
 		if s.isOrderedPipelineSupported {
@@ -99,6 +161,11 @@ func (s *Session) setupTest(pipelines pipeline.Pipelines, configFiles []logstash
 		if err != nil {
 			return err
 		}
+		for id, pattern := range configPatterns {
+			if ok, _ := doublestar.PathMatch(pattern, filepath.Join(sutConfigDir, configFile.Name)); ok {
+				s.pipelineFiles[id] = append(s.pipelineFiles[id], configFile)
+			}
+		}
 
 		outputPipelines, err := s.createOutputPipelines(outputs)
 		if err != nil {
@@ -108,6 +175,13 @@ func (s *Session) setupTest(pipelines pipeline.Pipelines, configFiles []logstash
 		pipelines = append(pipelines, outputPipelines...)
 	}
 
+	// Logstash concatenates the files of a pipeline in lexical order.
+	for _, files := range s.pipelineFiles {
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].Name < files[j].Name
+		})
+	}
+
 	// Reload Logstash Config
 	s.pipelines = pipelines
 	// err = s.logstash.ReloadPipelines(pipelines)
@@ -160,10 +234,11 @@ func (s *Session) createOutputPipelines(outputs []string) ([]pipeline.Pipeline,
 // ExecuteTest runs a test case set against the Logstash configuration, that has
 // been loaded previously with SetupTest.
 func (s *Session) ExecuteTest(inputPlugin string, inputLines []string, inEvents []map[string]interface{}, expectedEvents int) error {
-	s.testexec++
-	pipelineName := fmt.Sprintf("lfv_input_%d", s.testexec)
-	inputDir := filepath.Join(s.sessionDir, "lfv_inputs", strconv.Itoa(s.testexec))
+	testexec := int(s.testexec.Add(1))
+	pipelineName := fmt.Sprintf("lfv_input_%d", testexec)
+	inputDir := filepath.Join(s.sessionDir, "lfv_inputs", strconv.Itoa(testexec))
 	inputPluginName := fmt.Sprintf("%s_%s_%s", "__lfv_input", s.id, inputPlugin)
+	s.pendingEventsIn = len(inputLines)
 	inputCodec, ok := s.inputPluginCodecs[inputPlugin]
 	if !ok {
 		inputCodec = "codec => plain"
@@ -198,7 +273,7 @@ func (s *Session) ExecuteTest(inputPlugin string, inputLines []string, inEvents
 	pipelines := append(s.pipelines, pipeline)
 	err = s.logstashController.ExecuteTest(pipelines, expectedEvents)
 	if err != nil {
-		return err
+		return s.translateError(err)
 	}
 
 	return nil
@@ -212,6 +287,12 @@ func prepareFields(fieldsFilename string, inEvents []map[string]interface{}) err
 		fields[id] = event
 	}
 
+	return writeFields(fieldsFilename, fields)
+}
+
+// writeFields writes the translate dictionary, which maps the ids of the
+// input lines to the fields to be set on the resulting events.
+func writeFields(fieldsFilename string, fields map[string]map[string]interface{}) error {
 	bfields, err := json.Marshal(fields)
 	if err != nil {
 		return err
@@ -225,15 +306,6 @@ func prepareFields(fieldsFilename string, inEvents []map[string]interface{}) err
 }
 
 func createInput(pipelineFilename string, fieldsFilename string, inputPluginName string, inputLines []string, inputCodec string) error {
-	for i := range inputLines {
-		var err error
-		inputLine, err := astutil.Quote(inputLines[i], ast.DoubleQuoted)
-		if err != nil {
-			inputLine = astutil.QuoteWithEscape(inputLines[i], ast.SingleQuoted)
-		}
-		inputLines[i] = inputLine
-	}
-
 	templateData := struct {
 		InputPluginName          string
 		InputLines               string
@@ -242,7 +314,7 @@ func createInput(pipelineFilename string, fieldsFilename string, inputPluginName
 		DummyEventInputIndicator string
 	}{
 		InputPluginName:          inputPluginName,
-		InputLines:               strings.Join(inputLines, ", "),
+		InputLines:               quoteInputLines(inputLines),
 		InputCodec:               inputCodec,
 		FieldsFilename:           fieldsFilename,
 		DummyEventInputIndicator: testcase.DummyEventInputIndicator,
@@ -255,14 +327,284 @@ func createInput(pipelineFilename string, fieldsFilename string, inputPluginName
 	return nil
 }
 
+// quoteInputLines returns the input lines quoted as Logstash strings and
+// separated by comma.
+func quoteInputLines(inputLines []string) string {
+	quoted := make([]string, 0, len(inputLines))
+	for _, line := range inputLines {
+		inputLine, err := astutil.Quote(line, ast.DoubleQuoted)
+		if err != nil {
+			inputLine = astutil.QuoteWithEscape(line, ast.SingleQuoted)
+		}
+		quoted = append(quoted, inputLine)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// BatchTest contains the input of a test case set, which is executed as
+// part of a batch.
+type BatchTest struct {
+	InputPlugin string
+	InputLines  []string
+	InEvents    []map[string]interface{}
+}
+
+// ExecuteTestBatch runs multiple test case sets against the Logstash
+// configuration, that has been loaded previously with SetupTest. In
+// contrast to ExecuteTest, all the test case sets are fed through a single
+// input pipeline and therefore only a single reload of the Logstash config
+// is necessary. The ids of the input lines are unique over all the test
+// case sets, use SplitResults to assign the results to the test case sets.
+func (s *Session) ExecuteTestBatch(tests []BatchTest, expectedEvents int) error {
+	testexec := int(s.testexec.Add(1))
+	pipelineName := fmt.Sprintf("lfv_input_%d", testexec)
+	inputDir := filepath.Join(s.sessionDir, "lfv_inputs", strconv.Itoa(testexec))
+
+	type batchInput struct {
+		Index           int
+		Offset          int
+		InputPluginName string
+		InputLines      string
+		InputCodec      string
+	}
+
+	inputs := make([]batchInput, 0, len(tests))
+	fields := make(map[string]map[string]interface{})
+	offset := 0
+	s.pendingEventsIn = 0
+	for i, test := range tests {
+		s.pendingEventsIn += len(test.InputLines)
+		// The generator input emits a default message, if no lines are
+		// given, so test case sets without input lines are skipped.
+		if len(test.InputLines) == 0 {
+			continue
+		}
+		inputCodec, ok := s.inputPluginCodecs[test.InputPlugin]
+		if !ok {
+			inputCodec = "codec => plain"
+		}
+		inputs = append(inputs, batchInput{
+			Index:           i,
+			Offset:          offset,
+			InputPluginName: fmt.Sprintf("%s_%s_%s", "__lfv_input", s.id, test.InputPlugin),
+			InputLines:      quoteInputLines(test.InputLines),
+			InputCodec:      inputCodec,
+		})
+		for j, event := range test.InEvents {
+			fields[strconv.Itoa(offset+j)] = event
+		}
+		offset += len(test.InputLines)
+	}
+
+	// Prepare input directory
+	err := os.MkdirAll(inputDir, 0700)
+	if err != nil {
+		return err
+	}
+
+	fieldsFilename := filepath.Join(inputDir, "fields.json")
+	err = writeFields(fieldsFilename, fields)
+	if err != nil {
+		return err
+	}
+
+	templateData := struct {
+		Inputs                   []batchInput
+		FieldsFilename           string
+		DummyEventInputIndicator string
+	}{
+		Inputs:                   inputs,
+		FieldsFilename:           fieldsFilename,
+		DummyEventInputIndicator: testcase.DummyEventInputIndicator,
+	}
+	pipelineFilename := filepath.Join(inputDir, "input.conf")
+	err = template.ToFile(pipelineFilename, inputBatchGenerator, templateData, 0600)
+	if err != nil {
+		return err
+	}
+
+	pipeline := pipeline.Pipeline{
+		ID:      pipelineName,
+		Config:  pipelineFilename,
+		Workers: 1,
+	}
+	if s.isOrderedPipelineSupported {
+		pipeline.Ordered = "true"
+	}
+	pipelines := append(s.pipelines, pipeline)
+	err = s.logstashController.ExecuteTest(pipelines, expectedEvents)
+	return s.translateError(err)
+}
+
+// SplitResults assigns the results of ExecuteTestBatch to the test case
+// sets by the id of the input line, each result originates from. The ids
+// of the results are adjusted to be relative to the test case set, so the
+// results are indistinguishable from the results of ExecuteTest.
+func SplitResults(results []string, tests []BatchTest) ([][]string, error) {
+	offsets, total := batchOffsets(tests)
+
+	split := make([][]string, len(tests))
+	for _, result := range results {
+		id := gjson.Get(result, "__lfv_metadata.__lfv_id")
+		if !id.Exists() {
+			return nil, errors.Errorf("result without input line id: %s", result)
+		}
+		globalID := int(id.Int())
+
+		i := batchTestCaseSet(offsets, total, globalID)
+		if i < 0 {
+			return nil, errors.Errorf("result with unknown input line id %d: %s", globalID, result)
+		}
+
+		result, err := sjson.Set(result, "__lfv_metadata.__lfv_id", strconv.Itoa(globalID-offsets[i]))
+		if err != nil {
+			return nil, err
+		}
+		split[i] = append(split[i], result)
+	}
+
+	return split, nil
+}
+
+// SplitLogs assigns the Logstash log lines, which report an event dropped
+// by a drop filter (see logstashconfig.DroppedMessage), to the test case
+// sets of ExecuteTestBatch by the id of the dropped event. Like with
+// SplitResults, the ids are adjusted to be relative to the test case set.
+// All the other log lines are returned as remaining log lines.
+func SplitLogs(lines []string, tests []BatchTest) (split [][]string, remaining []string) {
+	offsets, total := batchOffsets(tests)
+
+	split = make([][]string, len(tests))
+	for _, line := range lines {
+		logEvent := gjson.Get(line, "logEvent")
+		if logEvent.Get("message").String() != logstashconfig.DroppedMessage {
+			remaining = append(remaining, line)
+			continue
+		}
+
+		globalID := int(logEvent.Get("event").Int())
+		i := batchTestCaseSet(offsets, total, globalID)
+		if i < 0 {
+			remaining = append(remaining, line)
+			continue
+		}
+
+		adjusted, err := sjson.Set(line, "logEvent.event", strconv.Itoa(globalID-offsets[i]))
+		if err != nil {
+			remaining = append(remaining, line)
+			continue
+		}
+		split[i] = append(split[i], adjusted)
+	}
+
+	return split, remaining
+}
+
+// batchOffsets returns the id of the first input line of each test case set
+// of a batch together with the total number of input lines.
+func batchOffsets(tests []BatchTest) ([]int, int) {
+	offsets := make([]int, len(tests))
+	offset := 0
+	for i, test := range tests {
+		offsets[i] = offset
+		offset += len(test.InputLines)
+	}
+	return offsets, offset
+}
+
+// batchTestCaseSet returns the index of the test case set of a batch, the
+// input line with the given id belongs to, or -1 if the id is unknown.
+func batchTestCaseSet(offsets []int, total int, id int) int {
+	if id < 0 || id >= total {
+		return -1
+	}
+
+	// Find the last test case set, which starts at or before the id.
+	// Test case sets without input lines share the offset with the
+	// following test case set and are skipped this way.
+	return sort.Search(len(offsets), func(i int) bool { return offsets[i] > id }) - 1
+}
+
 // GetResults returns the returned events from Logstash.
 func (s *Session) GetResults() ([]string, error) {
-	return s.logstashController.GetResults()
+	results, err := s.logstashController.GetResults()
+	s.recordExecution(len(results))
+	return results, s.translateError(err)
+}
+
+// StreamResults passes the events and the relevant Logstash log lines to
+// onEvent and onLog as soon as they arrive from Logstash.
+func (s *Session) StreamResults(onEvent func(event string) error, onLog func(line string) error) error {
+	eventsOut := 0
+	err := s.logstashController.StreamResults(
+		func(event string) error {
+			eventsOut++
+			return onEvent(event)
+		},
+		func(line string) error {
+			return onLog(s.translateLog(line))
+		},
+	)
+	s.recordExecution(eventsOut)
+	return s.translateError(err)
+}
+
+// translateLog translates the positions in the compile errors of a Logstash
+// log line into positions in the original Logstash config files.
+func (s *Session) translateLog(line string) string {
+	gjson.Get(line, "logEvent").ForEach(func(_, value gjson.Result) bool {
+		if value.Type != gjson.String {
+			return true
+		}
+		translated := logstashconfig.TranslateError(value.String(), s.allPipelineFiles())
+		if translated == value.String() {
+			return true
+		}
+		raw, err := json.Marshal(translated)
+		if err != nil {
+			return true
+		}
+		line = strings.Replace(line, value.Raw, string(raw), 1)
+		return true
+	})
+	return line
 }
 
-// GetStats returns the statistics for a test suite.
-func (s *Session) GetStats() {
-	panic("not implemented")
+// translateError translates the pipeline IDs and the positions in the
+// errors of pipelines, which Logstash failed to load, back to the pipeline
+// IDs and Logstash config files of the client.
+func (s *Session) translateError(err error) error {
+	var loadErr pipeline.LoadError
+	if !errors.As(err, &loadErr) {
+		return err
+	}
+
+	prefix := fmt.Sprintf("lfv_%s_", s.id)
+	messages := make(map[string]string, len(loadErr.Messages))
+	for id, message := range loadErr.Messages {
+		pipelines := s.allPipelineFiles()
+		if files, ok := s.pipelineFiles[id]; ok {
+			pipelines = [][]logstashconfig.File{files}
+		}
+		messages[strings.TrimPrefix(id, prefix)] = logstashconfig.TranslateError(message, pipelines)
+	}
+	return pipeline.LoadError{Messages: messages}
+}
+
+// allPipelineFiles returns the Logstash config files of all the pipelines
+// under test ordered by the ID of the pipeline.
+func (s *Session) allPipelineFiles() [][]logstashconfig.File {
+	ids := make([]string, 0, len(s.pipelineFiles))
+	for id := range s.pipelineFiles {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	pipelines := make([][]logstashconfig.File, 0, len(ids))
+	for _, id := range ids {
+		pipelines = append(pipelines, s.pipelineFiles[id])
+	}
+	return pipelines
 }
 
 func (s *Session) teardown() error {
@@ -0,0 +1,457 @@
+package session_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/file"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/logstashconfig"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pipeline"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pool"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/session"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
+)
+
+func TestExecuteTestBatch(t *testing.T) {
+	is := is.New(t)
+
+	tempdir := t.TempDir()
+
+	executions := 0
+	pool := &PoolMock{
+		GetFunc: func() (pool.LogstashController, error) {
+			logstashController := &LogstashControllerMock{
+				SetupTestFunc: func(pipelines pipeline.Pipelines) error {
+					return nil
+				},
+				TeardownFunc: func() error {
+					return nil
+				},
+				ExecuteTestFunc: func(pipelines pipeline.Pipelines, expectedEvents int) error {
+					executions++
+					is.Equal(len(pipelines), 3) // Expect 3 pipelines (input, main, output)
+					is.Equal(expectedEvents, 3) // Expected events of all test case sets
+					return nil
+				},
+			}
+			return logstashController, nil
+		},
+		ReturnFunc: func(instance pool.LogstashController, clean bool) {},
+	}
+
+	c := session.NewController(tempdir, pool, 2, false, true, logging.NoopLogger)
+
+	pipelines := pipeline.Pipelines{
+		pipeline.Pipeline{
+			ID:      "main",
+			Config:  "main.conf",
+			Workers: 1,
+		},
+	}
+
+	configFiles := []logstashconfig.File{
+		{
+			Name: "main.conf",
+			Body: []byte(`input { stdin{ id => first } stdin{ id => second codec => json } } output { stdout{} }`),
+		},
+	}
+
+	s, err := c.Create(pipelines, configFiles)
+	is.NoErr(err)
+
+	tests := []session.BatchTest{
+		{
+			InputPlugin: "first",
+			InputLines:  []string{"a", "b"},
+			InEvents:    []map[string]interface{}{{"first_key": "a"}, {"first_key": "b"}},
+		},
+		{
+			InputPlugin: "second",
+			InputLines:  []string{`{"c": 1}`},
+			InEvents:    []map[string]interface{}{{"second_key": "c"}},
+		},
+	}
+	err = s.ExecuteTestBatch(tests, 3)
+	is.NoErr(err)
+	is.Equal(executions, 1) // Single reload for all test case sets
+
+	inputDir := filepath.Join(tempdir, "session", s.ID(), "lfv_inputs", "1")
+	is.True(file.Contains(filepath.Join(inputDir, "fields.json"), `"2":{"second_key":"c"}`))                // fields.json contains the ids of all test case sets
+	is.True(file.Contains(filepath.Join(inputDir, "input.conf"), `"[@metadata][__lfv_offset]" => "2"`))     // input.conf contains offset of second test case set
+	is.True(file.Contains(filepath.Join(inputDir, "input.conf"), "codec => json"))                          // input.conf contains codec of second input
+	is.True(file.Contains(filepath.Join(inputDir, "input.conf"), "__lfv_input_"+s.ID()+"_second"))          // input.conf sends to second input
+	is.True(file.Contains(filepath.Join(inputDir, "input.conf"), `else if [@metadata][__lfv_set] == "1"`))  // input.conf routes second test case set
+	is.True(!file.Contains(filepath.Join(inputDir, "input.conf"), `else if [@metadata][__lfv_set] == "0"`)) // first condition without else
+
+	err = c.DestroyByID(s.ID())
+	is.NoErr(err)
+
+	<-c.WaitFinish()
+}
+
+func TestExecuteTestLoadError(t *testing.T) {
+	is := is.New(t)
+
+	tempdir := t.TempDir()
+
+	var sessionID string
+	pool := &PoolMock{
+		GetFunc: func() (pool.LogstashController, error) {
+			logstashController := &LogstashControllerMock{
+				SetupTestFunc: func(pipelines pipeline.Pipelines) error {
+					return nil
+				},
+				TeardownFunc: func() error {
+					return nil
+				},
+				ExecuteTestFunc: func(pipelines pipeline.Pipelines, expectedEvents int) error {
+					// The files of the pipeline "other" are concatenated by
+					// Logstash, the error is in the second line of b.conf.
+					first, err := os.ReadFile(filepath.Join(tempdir, "session", sessionID, "sut", "conf.d", "a.conf"))
+					is.NoErr(err)
+					line := bytes.Count(first, []byte("\n")) + 3
+					return pipeline.LoadError{Messages: map[string]string{
+						"lfv_" + sessionID + "_main":  `Expected one of [ \t\r\n], "#", "{" at line 1, column 9 (byte 9) after filter {`,
+						"lfv_" + sessionID + "_other": fmt.Sprintf(`Expected one of [ \t\r\n], "#", "{" at line %d, column 3 (byte 100) after %s`, line, first),
+					}}
+				},
+			}
+			return logstashController, nil
+		},
+		ReturnFunc: func(instance pool.LogstashController, clean bool) {},
+	}
+
+	c := session.NewController(tempdir, pool, 1, false, true, logging.NoopLogger)
+
+	pipelines := pipeline.Pipelines{
+		pipeline.Pipeline{
+			ID:      "main",
+			Config:  "main.conf",
+			Workers: 1,
+		},
+		pipeline.Pipeline{
+			ID:      "other",
+			Config:  "conf.d/*.conf",
+			Workers: 1,
+		},
+	}
+
+	configFiles := []logstashconfig.File{
+		{
+			Name: "main.conf",
+			Body: []byte(`filter { mutate { id => mutate } }`),
+		},
+		{
+			Name: "conf.d/b.conf",
+			Body: []byte("filter {\n  mutate { id => \"b\" }\n}\n"),
+		},
+		{
+			Name: "conf.d/a.conf",
+			Body: []byte(`filter { mutate { id => "a" } }`),
+		},
+	}
+
+	s, err := c.Create(pipelines, configFiles)
+	is.NoErr(err)
+	sessionID = s.ID()
+
+	err = s.ExecuteTest("stdin", []string{"a"}, []map[string]interface{}{{}}, 1)
+
+	var loadErr pipeline.LoadError
+	is.True(errors.As(err, &loadErr))                                                                                                                                      // load error is returned
+	is.Equal(loadErr.Messages["main"], `Expected one of [ \t\r\n], "#", "{" within main.conf:1:1 (line 1, column 9 of the rewritten config) after filter {`)               // translated pipeline ID and position
+	is.True(strings.HasPrefix(loadErr.Messages["other"], `Expected one of [ \t\r\n], "#", "{" within conf.d/b.conf:2:3 (line 2, column 3 of the rewritten config) after`)) // position in second file of pipeline
+
+	err = c.DestroyByID(s.ID())
+	is.NoErr(err)
+
+	<-c.WaitFinish()
+}
+
+func TestSplitResults(t *testing.T) {
+	tests := []session.BatchTest{
+		{InputLines: []string{"a", "b"}},
+		{InputLines: []string{}},
+		{InputLines: []string{"c"}},
+	}
+
+	cases := []struct {
+		name    string
+		results []string
+
+		want    [][]string
+		wantErr bool
+	}{
+		{
+			name: "success",
+			results: []string{
+				`{"message":"c","__lfv_metadata":{"__lfv_id":"2"}}`,
+				`{"message":"a","__lfv_metadata":{"__lfv_id":"0"}}`,
+				`{"message":"b","__lfv_metadata":{"__lfv_id":"1"}}`,
+				`{"message":"c2","__lfv_metadata":{"__lfv_id":"2"}}`,
+			},
+
+			want: [][]string{
+				{
+					`{"message":"a","__lfv_metadata":{"__lfv_id":"0"}}`,
+					`{"message":"b","__lfv_metadata":{"__lfv_id":"1"}}`,
+				},
+				nil,
+				{
+					`{"message":"c","__lfv_metadata":{"__lfv_id":"0"}}`,
+					`{"message":"c2","__lfv_metadata":{"__lfv_id":"0"}}`,
+				},
+			},
+		},
+		{
+			name:    "missing id",
+			results: []string{`{"message":"a"}`},
+
+			wantErr: true,
+		},
+		{
+			name:    "unknown id",
+			results: []string{`{"message":"a","__lfv_metadata":{"__lfv_id":"3"}}`},
+
+			wantErr: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			got, err := session.SplitResults(test.results, tests)
+			is.Equal(err != nil, test.wantErr) // error
+			if test.wantErr {
+				return
+			}
+			is.Equal(got, test.want)
+		})
+	}
+}
+
+func TestSplitLogs(t *testing.T) {
+	is := is.New(t)
+
+	tests := []session.BatchTest{
+		{InputLines: []string{"a", "b"}},
+		{InputLines: []string{}},
+		{InputLines: []string{"c"}},
+	}
+	lines := []string{
+		`{"level":"WARN","logEvent":{"message":"__lfv_dropped","id":"drop","event":"2"}}`,
+		`{"level":"WARN","logEvent":{"message":"pipeline warning"}}`,
+		`{"level":"WARN","logEvent":{"message":"__lfv_dropped","id":"drop","event":"1"}}`,
+		`{"level":"WARN","logEvent":{"message":"__lfv_dropped","id":"drop","event":"3"}}`,
+	}
+
+	split, remaining := session.SplitLogs(lines, tests)
+
+	is.Equal(split, [][]string{
+		{`{"level":"WARN","logEvent":{"message":"__lfv_dropped","id":"drop","event":"1"}}`},
+		nil,
+		{`{"level":"WARN","logEvent":{"message":"__lfv_dropped","id":"drop","event":"0"}}`},
+	})
+	is.Equal(remaining, []string{
+		`{"level":"WARN","logEvent":{"message":"pipeline warning"}}`,
+		`{"level":"WARN","logEvent":{"message":"__lfv_dropped","id":"drop","event":"3"}}`,
+	}) // log lines not belonging to a test case set
+}
+
+func TestStats(t *testing.T) {
+	cases := []struct {
+		name           string
+		pluginStatsErr error
+
+		wantPlugins []pipeline.PluginStats
+		wantErr     bool
+	}{
+		{
+			name: "success",
+
+			wantPlugins: []pipeline.PluginStats{
+				{PipelineID: "main", ID: "grok", Name: "grok", Kind: "filter", EventsIn: 3, EventsOut: 3, Duration: 5 * time.Millisecond},
+			},
+		},
+		{
+			name:           "plugin stats error",
+			pluginStatsErr: errors.New("error"),
+
+			wantErr: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			tempdir := t.TempDir()
+
+			var s *session.Session
+			results := [][]string{{"a", "b"}, {"c"}}
+			pool := &PoolMock{
+				GetFunc: func() (pool.LogstashController, error) {
+					logstashController := &LogstashControllerMock{
+						SetupTestFunc: func(pipelines pipeline.Pipelines) error {
+							return nil
+						},
+						TeardownFunc: func() error {
+							return nil
+						},
+						ExecuteTestFunc: func(pipelines pipeline.Pipelines, expectedEvents int) error {
+							return nil
+						},
+						GetResultsFunc: func() ([]string, error) {
+							res := results[0]
+							results = results[1:]
+							return res, nil
+						},
+						ExecutionTimesFunc: func() (time.Duration, time.Duration) {
+							return 2 * time.Second, 10 * time.Millisecond
+						},
+						PluginStatsFunc: func(pipelineIDs ...string) ([]pipeline.PluginStats, error) {
+							is.Equal(pipelineIDs, []string{"lfv_" + s.ID() + "_main"}) // only the pipelines under test
+							if test.pluginStatsErr != nil {
+								return nil, test.pluginStatsErr
+							}
+							return []pipeline.PluginStats{
+								{PipelineID: "lfv_" + s.ID() + "_main", ID: "grok", Name: "grok", Kind: "filter", EventsIn: 3, EventsOut: 3, Duration: 5 * time.Millisecond},
+							}, nil
+						},
+					}
+					return logstashController, nil
+				},
+				ReturnFunc: func(instance pool.LogstashController, clean bool) {},
+			}
+
+			c := session.NewController(tempdir, pool, 1, false, true, logging.NoopLogger)
+
+			pipelines := pipeline.Pipelines{
+				pipeline.Pipeline{
+					ID:      "main",
+					Config:  "main.conf",
+					Workers: 1,
+				},
+			}
+
+			configFiles := []logstashconfig.File{
+				{
+					Name: "main.conf",
+					Body: []byte(`input { stdin{ id => testid } } output { stdout{ id => out } }`),
+				},
+			}
+
+			var err error
+			s, err = c.Create(pipelines, configFiles)
+			is.NoErr(err)
+
+			err = s.ExecuteTest("testid", []string{"a", "b", "c"}, nil, 2)
+			is.NoErr(err)
+			_, err = s.GetResults()
+			is.NoErr(err)
+
+			err = s.ExecuteTest("testid", []string{"d"}, nil, 1)
+			is.NoErr(err)
+			_, err = s.GetResults()
+			is.NoErr(err)
+
+			stats, err := s.Stats()
+			is.Equal(err != nil, test.wantErr) // Stats error
+			is.Equal(stats.TestExecutions, 2)
+			is.Equal(stats.EventsIn, 4)
+			is.Equal(stats.EventsOut, 3)
+			is.Equal(stats.Executions, []session.ExecutionStats{
+				{EventsIn: 3, EventsOut: 2, Reload: 2 * time.Second, Processing: 10 * time.Millisecond},
+				{EventsIn: 1, EventsOut: 1, Reload: 2 * time.Second, Processing: 10 * time.Millisecond},
+			})
+			is.Equal(stats.Plugins, test.wantPlugins)
+
+			err = c.DestroyByID(s.ID())
+			is.NoErr(err)
+		})
+	}
+}
+
+func TestDumpConfig(t *testing.T) {
+	is := is.New(t)
+
+	tempdir := t.TempDir()
+
+	pool := &PoolMock{
+		GetFunc: func() (pool.LogstashController, error) {
+			logstashController := &LogstashControllerMock{
+				SetupTestFunc: func(pipelines pipeline.Pipelines) error {
+					return nil
+				},
+				TeardownFunc: func() error {
+					return nil
+				},
+				ExecuteTestFunc: func(pipelines pipeline.Pipelines, expectedEvents int) error {
+					return nil
+				},
+				PipelinesConfigFunc: func() ([]byte, error) {
+					return []byte("- pipeline.id: stdin\n"), nil
+				},
+			}
+			return logstashController, nil
+		},
+		ReturnFunc: func(instance pool.LogstashController, clean bool) {},
+	}
+
+	c := session.NewController(tempdir, pool, 1, false, true, logging.NoopLogger)
+
+	pipelines := pipeline.Pipelines{
+		pipeline.Pipeline{
+			ID:      "main",
+			Config:  "main.conf",
+			Workers: 1,
+		},
+	}
+
+	configFiles := []logstashconfig.File{
+		{
+			Name: "main.conf",
+			Body: []byte(`input { stdin { id => stdin } } filter { mutate { id => mutate } } output { stdout { id => stdout } }`),
+		},
+	}
+
+	s, err := c.Create(pipelines, configFiles)
+	is.NoErr(err)
+
+	err = s.ExecuteTest("stdin", []string{"a"}, []map[string]interface{}{{}}, 1)
+	is.NoErr(err)
+
+	archive, err := s.DumpConfig()
+	is.NoErr(err)
+
+	r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	is.NoErr(err)
+	names := make([]string, 0, len(r.File))
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	is.Equal(names, []string{
+		"lfv_inputs/1/fields.json",
+		"lfv_inputs/1/input.conf",
+		"lfv_outputs/stdout.conf",
+		"pipelines.yml",
+		"sut/main.conf",
+	})
+
+	err = c.DestroyByID(s.ID())
+	is.NoErr(err)
+
+	<-c.WaitFinish()
+}
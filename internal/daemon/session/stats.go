@@ -0,0 +1,79 @@
+package session
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/pipeline"
+)
+
+// Stats contains the statistics of a session.
+type Stats struct {
+	TestExecutions int
+	EventsIn       int
+	EventsOut      int
+	Executions     []ExecutionStats
+	Plugins        []pipeline.PluginStats
+}
+
+// ExecutionStats contains the statistics of a single test execution.
+type ExecutionStats struct {
+	EventsIn  int
+	EventsOut int
+
+	// Reload is the time from the start of the test execution until the
+	// Logstash pipelines are running.
+	Reload time.Duration
+	// Processing is the time from the running pipelines until the test
+	// execution is complete.
+	Processing time.Duration
+}
+
+// recordExecution records the statistics of the current test execution
+// after its results have been received.
+func (s *Session) recordExecution(eventsOut int) {
+	reload, processing := s.logstashController.ExecutionTimes()
+	s.executions = append(s.executions, ExecutionStats{
+		EventsIn:   s.pendingEventsIn,
+		EventsOut:  eventsOut,
+		Reload:     reload,
+		Processing: processing,
+	})
+	s.pendingEventsIn = 0
+}
+
+// Stats returns the statistics of the session. The plugin statistics are
+// retrieved from Logstash and cover the pipelines of the Logstash
+// configuration under test. If they are not available, the remaining
+// statistics are returned together with the error.
+func (s *Session) Stats() (Stats, error) {
+	stats := Stats{
+		TestExecutions: int(s.testexec.Load()),
+		Executions:     s.executions,
+	}
+	for _, execution := range s.executions {
+		stats.EventsIn += execution.EventsIn
+		stats.EventsOut += execution.EventsOut
+	}
+
+	prefix := "lfv_" + s.id + "_"
+	pipelineIDs := make([]string, 0, len(s.pipelines))
+	for _, pipeline := range s.pipelines {
+		if strings.HasPrefix(pipeline.ID, prefix) {
+			pipelineIDs = append(pipelineIDs, pipeline.ID)
+		}
+	}
+
+	plugins, err := s.logstashController.PluginStats(pipelineIDs...)
+	if err != nil {
+		return stats, errors.Wrap(err, "failed to get plugin statistics")
+	}
+	for i := range plugins {
+		plugins[i].PipelineID = strings.TrimPrefix(plugins[i].PipelineID, prefix)
+	}
+	stats.Plugins = plugins
+
+	return stats, nil
+}
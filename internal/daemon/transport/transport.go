@@ -0,0 +1,212 @@
+// Package transport provides the network connection between the daemon and
+// its clients, either over a unix domain socket or over TCP, optionally
+// secured with (mutual) TLS.
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	tcpScheme  = "tcp://"
+	unixScheme = "unix://"
+)
+
+// Parse splits the address of the daemon in network and address. The
+// address is either tcp://host:port, unix:///path/to/socket or just the path
+// of a unix domain socket.
+func Parse(address string) (network string, addr string, err error) {
+	switch {
+	case strings.HasPrefix(address, tcpScheme):
+		addr = strings.TrimPrefix(address, tcpScheme)
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return "", "", errors.Wrapf(err, "invalid address %q", address)
+		}
+		return "tcp", addr, nil
+	case strings.HasPrefix(address, unixScheme):
+		return "unix", strings.TrimPrefix(address, unixScheme), nil
+	case strings.Contains(address, "://"):
+		return "", "", errors.Errorf("invalid address %q, only tcp:// and unix:// are supported", address)
+	default:
+		return "unix", address, nil
+	}
+}
+
+// Listen announces on the address of the daemon (see Parse).
+func Listen(address string) (net.Listener, error) {
+	network, addr, err := Parse(address)
+	if err != nil {
+		return nil, err
+	}
+	return net.Listen(network, addr)
+}
+
+// CheckListenAddress returns an error, if the daemon would accept
+// connections from other hosts without verifying the certificates of the
+// clients. Every client able to connect can execute arbitrary code on the
+// daemon host (e.g. with the ruby filter) and shut down the daemon, so a
+// tcp:// address, which is not a loopback address, requires mutual TLS.
+func CheckListenAddress(address string, tlsConfig *tls.Config) error {
+	network, addr, err := Parse(address)
+	if err != nil {
+		return err
+	}
+	if network != "tcp" {
+		return nil
+	}
+	if tlsConfig != nil && tlsConfig.ClientAuth == tls.RequireAndVerifyClientCert {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return errors.Wrapf(err, "invalid address %q", address)
+	}
+	if isLoopback(host) {
+		return nil
+	}
+	return errors.Errorf("listening on %s requires client certificates (--tls-cert, --tls-key and --tls-ca), use --insecure to listen without them", address)
+}
+
+// isLoopback returns true, if host only accepts connections from the local
+// host.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Dial creates a client connection to the daemon at the given address (see
+// Parse). If tlsConfig is not nil, the connection is secured with TLS, which
+// is only supported for TCP.
+func Dial(address string, tlsConfig *tls.Config, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	network, addr, err := Parse(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if network == "tcp" {
+		creds := insecure.NewCredentials()
+		if tlsConfig != nil {
+			creds = credentials.NewTLS(tlsConfig)
+		}
+		return grpc.Dial(addr, append(opts, grpc.WithTransportCredentials(creds))...)
+	}
+
+	if tlsConfig != nil {
+		return nil, errors.New("TLS is only supported for tcp:// addresses")
+	}
+	return grpc.Dial(
+		addr,
+		append(opts,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+				if d, ok := ctx.Deadline(); ok {
+					return net.DialTimeout("unix", addr, time.Until(d))
+				}
+				return net.Dial("unix", addr)
+			}),
+		)...,
+	)
+}
+
+// TLSFiles contains the paths of the PEM encoded files for TLS.
+type TLSFiles struct {
+	// Cert is the certificate of the daemon or the client.
+	Cert string
+	// Key is the private key belonging to Cert.
+	Key string
+	// CA is the certificate authority used to verify the certificate of
+	// the other side. If set on the daemon, clients need to present a
+	// certificate signed by this CA (mutual TLS).
+	CA string
+}
+
+// IsEmpty returns true, if none of the files is given.
+func (f TLSFiles) IsEmpty() bool {
+	return f.Cert == "" && f.Key == "" && f.CA == ""
+}
+
+// ServerConfig returns the TLS config for the daemon or nil, if no TLS
+// files are given.
+func (f TLSFiles) ServerConfig() (*tls.Config, error) {
+	if f.IsEmpty() {
+		return nil, nil
+	}
+	if f.Cert == "" || f.Key == "" {
+		return nil, errors.New("TLS requires both a certificate and a key for the daemon")
+	}
+
+	cert, err := tls.LoadX509KeyPair(f.Cert, f.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load TLS certificate")
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if f.CA != "" {
+		config.ClientCAs, err = loadCertPool(f.CA)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// ClientConfig returns the TLS config for a client or nil, if no TLS files
+// are given. Without a CA, the certificate of the daemon is verified with
+// the system certificate pool.
+func (f TLSFiles) ClientConfig() (*tls.Config, error) {
+	if f.IsEmpty() {
+		return nil, nil
+	}
+	if (f.Cert == "") != (f.Key == "") {
+		return nil, errors.New("TLS requires both a certificate and a key for the client")
+	}
+
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if f.Cert != "" {
+		cert, err := tls.LoadX509KeyPair(f.Cert, f.Key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load TLS certificate")
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	if f.CA != "" {
+		var err error
+		config.RootCAs, err = loadCertPool(f.CA)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+func loadCertPool(filename string) (*x509.CertPool, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read TLS CA")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, errors.Errorf("no certificates found in TLS CA %s", filename)
+	}
+	return pool, nil
+}
@@ -0,0 +1,264 @@
+package transport_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	pb "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/api/grpc"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/daemon/transport"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name    string
+		address string
+
+		wantNetwork string
+		wantAddr    string
+		wantErr     bool
+	}{
+		{
+			name:    "socket path",
+			address: "/tmp/lfv.sock",
+
+			wantNetwork: "unix",
+			wantAddr:    "/tmp/lfv.sock",
+		},
+		{
+			name:    "unix scheme",
+			address: "unix:///tmp/lfv.sock",
+
+			wantNetwork: "unix",
+			wantAddr:    "/tmp/lfv.sock",
+		},
+		{
+			name:    "tcp",
+			address: "tcp://localhost:9000",
+
+			wantNetwork: "tcp",
+			wantAddr:    "localhost:9000",
+		},
+		{
+			name:    "tcp without port",
+			address: "tcp://localhost",
+
+			wantErr: true,
+		},
+		{
+			name:    "unsupported scheme",
+			address: "http://localhost:9000",
+
+			wantErr: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			network, addr, err := transport.Parse(test.address)
+			is.Equal(err != nil, test.wantErr) // error
+			is.Equal(network, test.wantNetwork)
+			is.Equal(addr, test.wantAddr)
+		})
+	}
+}
+
+func TestCheckListenAddress(t *testing.T) {
+	cases := []struct {
+		name      string
+		address   string
+		tlsConfig *tls.Config
+
+		wantErr bool
+	}{
+		{
+			name:    "socket path",
+			address: "/tmp/lfv.sock",
+		},
+		{
+			name:    "localhost",
+			address: "tcp://localhost:9000",
+		},
+		{
+			name:    "loopback ipv4",
+			address: "tcp://127.0.0.1:9000",
+		},
+		{
+			name:    "loopback ipv6",
+			address: "tcp://[::1]:9000",
+		},
+		{
+			name:      "mutual TLS",
+			address:   "tcp://0.0.0.0:9000",
+			tlsConfig: &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert},
+		},
+		{
+			name:    "without TLS",
+			address: "tcp://0.0.0.0:9000",
+
+			wantErr: true,
+		},
+		{
+			name:    "all interfaces",
+			address: "tcp://:9000",
+
+			wantErr: true,
+		},
+		{
+			name:      "TLS without client certificates",
+			address:   "tcp://lfv.example.com:9000",
+			tlsConfig: &tls.Config{},
+
+			wantErr: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			err := transport.CheckListenAddress(test.address, test.tlsConfig)
+			is.Equal(err != nil, test.wantErr) // error
+		})
+	}
+}
+
+type shutdownServer struct {
+	pb.UnimplementedControlServer
+}
+
+func (shutdownServer) Shutdown(context.Context, *pb.ShutdownRequest) (*pb.ShutdownResponse, error) {
+	return &pb.ShutdownResponse{}, nil
+}
+
+func TestDialMutualTLS(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	ca, caKey := writeCert(t, dir, "ca", nil, nil)
+	writeCert(t, dir, "server", ca, caKey)
+	writeCert(t, dir, "client", ca, caKey)
+
+	serverConfig, err := transport.TLSFiles{
+		Cert: filepath.Join(dir, "server.crt"),
+		Key:  filepath.Join(dir, "server.key"),
+		CA:   filepath.Join(dir, "ca.crt"),
+	}.ServerConfig()
+	is.NoErr(err)
+
+	lis, err := transport.Listen("tcp://127.0.0.1:0")
+	is.NoErr(err)
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(serverConfig)))
+	pb.RegisterControlServer(server, shutdownServer{})
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	address := "tcp://" + lis.Addr().String()
+
+	cases := []struct {
+		name  string
+		files transport.TLSFiles
+
+		wantErr bool
+	}{
+		{
+			name: "client certificate",
+			files: transport.TLSFiles{
+				Cert: filepath.Join(dir, "client.crt"),
+				Key:  filepath.Join(dir, "client.key"),
+				CA:   filepath.Join(dir, "ca.crt"),
+			},
+		},
+		{
+			name: "without client certificate",
+			files: transport.TLSFiles{
+				CA: filepath.Join(dir, "ca.crt"),
+			},
+
+			wantErr: true,
+		},
+		{
+			name: "without TLS",
+
+			wantErr: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			clientConfig, err := test.files.ClientConfig()
+			is.NoErr(err)
+
+			conn, err := transport.Dial(address, clientConfig)
+			is.NoErr(err)
+			defer conn.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, err = pb.NewControlClient(conn).Shutdown(ctx, &pb.ShutdownRequest{})
+			is.Equal(err != nil, test.wantErr) // shutdown error
+		})
+	}
+}
+
+// writeCert writes a certificate and its key as name.crt and name.key to
+// dir. Without a parent, a self signed CA certificate is created.
+func writeCert(t *testing.T, dir string, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	is := is.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	is.NoErr(err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	is.NoErr(err)
+	cert, err := x509.ParseCertificate(der)
+	is.NoErr(err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	is.NoErr(err)
+
+	err = os.WriteFile(filepath.Join(dir, name+".crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	is.NoErr(err)
+	err = os.WriteFile(filepath.Join(dir, name+".key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	is.NoErr(err)
+
+	return cert, key
+}
@@ -0,0 +1,303 @@
+// Package diff implements a structured comparison of Logstash events.
+// Instead of comparing the textual representation of two events, both
+// events are walked and the differences are reported field by field,
+// each one identified by its path in the Logstash field reference
+// notation (e.g. [log][file][path]).
+//
+// Matchers can be used in expected values instead of a literal value. A
+// matcher is an object, whose keys all start with "$", e.g.
+//
+//	{"$regex": "^\\d{4}-"}
+//
+// The following matchers are supported:
+//
+//	$regex      the actual value is a string matching the regular expression
+//	$type       the actual value is of the given JSON type (string, number,
+//	            boolean, object, array, null)
+//	$any        the actual value is present, regardless of its value
+//	$contains   the actual value is an array containing all the given
+//	            values (a single value may be given instead of an array)
+//	$approx     the actual value is a number, which differs at most by
+//	            $tolerance (default: 1e-9) from the given number
+//	$range      the actual value is a number within the given [min, max]
+//	            interval (inclusive)
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kind describes the kind of a difference between two values.
+type Kind string
+
+const (
+	// Missing is reported for fields, which are present in the expected
+	// value, but not in the actual value.
+	Missing Kind = "missing"
+
+	// Extra is reported for fields, which are present in the actual
+	// value, but not in the expected value.
+	Extra Kind = "extra"
+
+	// Type is reported, if the expected and the actual value are of a
+	// different JSON type (e.g. string and number).
+	Type Kind = "type"
+
+	// Value is reported, if the expected and the actual value are of the
+	// same JSON type but differ in their value.
+	Value Kind = "value"
+
+	// Match is reported, if the actual value does not satisfy the matcher
+	// given as expected value.
+	Match Kind = "match"
+)
+
+// Difference describes a single difference between an expected and an
+// actual value.
+type Difference struct {
+	// Path of the field in Logstash field reference notation, e.g.
+	// [log][file][path]. Array elements are referenced by their index,
+	// e.g. [tags][0]. The path is empty for a difference of the root
+	// values.
+	Path string `json:"path"`
+
+	Kind Kind `json:"kind"`
+
+	// Expected and Actual contain the respective values at Path. Expected
+	// is nil for differences of kind Extra, Actual is nil for differences
+	// of kind Missing.
+	Expected interface{} `json:"expected,omitempty"`
+	Actual   interface{} `json:"actual,omitempty"`
+
+	// Message describes, why the actual value does not satisfy the
+	// matcher. It is only set for differences of kind Match.
+	Message string `json:"message,omitempty"`
+}
+
+// String returns a human readable description of the difference.
+func (d Difference) String() string {
+	path := d.Path
+	if path == "" {
+		path = "<root>"
+	}
+	switch d.Kind {
+	case Missing:
+		return fmt.Sprintf("%s: missing field, expected %s", path, format(d.Expected))
+	case Extra:
+		return fmt.Sprintf("%s: unexpected field with value %s", path, format(d.Actual))
+	case Match:
+		return fmt.Sprintf("%s: %s", path, d.Message)
+	case Type:
+		return fmt.Sprintf("%s: type mismatch, expected %s %s, got %s %s", path, typeName(d.Expected), format(d.Expected), typeName(d.Actual), format(d.Actual))
+	default:
+		return fmt.Sprintf("%s: value mismatch, expected %s, got %s", path, format(d.Expected), format(d.Actual))
+	}
+}
+
+// Compare compares expected and actual and returns all the differences
+// found, ordered by path. Both values are normalized to their JSON
+// representation first, such that e.g. different numeric types are
+// compared by their JSON value. Matchers in expected are evaluated against
+// the respective actual value. An empty result means, that both values are
+// equal. A non-nil error value indicates an invalid matcher.
+func Compare(expected, actual interface{}) ([]Difference, error) {
+	normalizedExpected, err := normalize(expected)
+	if err != nil {
+		return nil, err
+	}
+	normalizedActual, err := normalize(actual)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []Difference
+	if err := compare("", normalizedExpected, normalizedActual, &diffs); err != nil {
+		return nil, err
+	}
+	return diffs, nil
+}
+
+// Explain returns the human readable description of the given
+// differences, one difference per line.
+func Explain(diffs []Difference) string {
+	var sb strings.Builder
+	for _, d := range diffs {
+		sb.WriteString(d.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func compare(path string, expected, actual interface{}, diffs *[]Difference) error {
+	if isMatcher(expected) {
+		reason, err := match(expected.(map[string]interface{}), actual)
+		if err != nil {
+			if path == "" {
+				return err
+			}
+			return fmt.Errorf("%s: %s", path, err)
+		}
+		if reason != "" {
+			*diffs = append(*diffs, Difference{Path: path, Kind: Match, Expected: expected, Actual: actual, Message: reason})
+		}
+		return nil
+	}
+
+	if typeName(expected) != typeName(actual) {
+		*diffs = append(*diffs, Difference{Path: path, Kind: Type, Expected: expected, Actual: actual})
+		return nil
+	}
+
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualValue := actual.(map[string]interface{})
+		for _, key := range unionKeys(expectedValue, actualValue) {
+			fieldPath := fmt.Sprintf("%s[%s]", path, key)
+			e, inExpected := expectedValue[key]
+			a, inActual := actualValue[key]
+			switch {
+			case !inActual:
+				*diffs = append(*diffs, Difference{Path: fieldPath, Kind: Missing, Expected: e})
+			case !inExpected:
+				*diffs = append(*diffs, Difference{Path: fieldPath, Kind: Extra, Actual: a})
+			default:
+				if err := compare(fieldPath, e, a, diffs); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		actualValue := actual.([]interface{})
+		for i := 0; i < len(expectedValue) || i < len(actualValue); i++ {
+			elementPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(actualValue):
+				*diffs = append(*diffs, Difference{Path: elementPath, Kind: Missing, Expected: expectedValue[i]})
+			case i >= len(expectedValue):
+				*diffs = append(*diffs, Difference{Path: elementPath, Kind: Extra, Actual: actualValue[i]})
+			default:
+				if err := compare(elementPath, expectedValue[i], actualValue[i], diffs); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		if expected != actual {
+			*diffs = append(*diffs, Difference{Path: path, Kind: Value, Expected: expected, Actual: actual})
+		}
+	}
+	return nil
+}
+
+// Subset returns a copy of actual, which only contains the fields, which
+// are present in expected. Nested objects are reduced recursively, as are
+// objects within arrays. Values in actual, which are compared against a
+// matcher, are kept as is. Comparing expected with the returned value
+// therefore ignores all the fields in actual, expected does not care about.
+func Subset(expected, actual interface{}) (interface{}, error) {
+	normalizedExpected, err := normalize(expected)
+	if err != nil {
+		return nil, err
+	}
+	normalizedActual, err := normalize(actual)
+	if err != nil {
+		return nil, err
+	}
+
+	return subset(normalizedExpected, normalizedActual), nil
+}
+
+func subset(expected, actual interface{}) interface{} {
+	if isMatcher(expected) {
+		return actual
+	}
+
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualValue, ok := actual.(map[string]interface{})
+		if !ok {
+			return actual
+		}
+		reduced := make(map[string]interface{}, len(expectedValue))
+		for key, e := range expectedValue {
+			if a, ok := actualValue[key]; ok {
+				reduced[key] = subset(e, a)
+			}
+		}
+		return reduced
+	case []interface{}:
+		actualValue, ok := actual.([]interface{})
+		if !ok {
+			return actual
+		}
+		reduced := make([]interface{}, len(actualValue))
+		for i, a := range actualValue {
+			reduced[i] = a
+			if i < len(expectedValue) {
+				reduced[i] = subset(expectedValue[i], a)
+			}
+		}
+		return reduced
+	default:
+		return actual
+	}
+}
+
+// normalize converts v into the generic representation used by
+// encoding/json, which consists only of maps with string keys, slices,
+// strings, float64, bool and nil.
+func normalize(v interface{}) (interface{}, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %+v as JSON: %s", v, err)
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(buf, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func format(v interface{}) string {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(buf)
+}
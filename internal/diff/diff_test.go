@@ -0,0 +1,127 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/diff"
+)
+
+func TestCompare(t *testing.T) {
+	cases := []struct {
+		name     string
+		expected interface{}
+		actual   interface{}
+
+		want []diff.Difference
+	}{
+		{
+			name:     "equal",
+			expected: map[string]interface{}{"message": "test", "count": 1, "tags": []interface{}{"a", "b"}},
+			actual:   map[string]interface{}{"message": "test", "count": 1.0, "tags": []string{"a", "b"}},
+		},
+		{
+			name:     "missing and extra field",
+			expected: map[string]interface{}{"a": "b"},
+			actual:   map[string]interface{}{"c": "d"},
+
+			want: []diff.Difference{
+				{Path: "[a]", Kind: diff.Missing, Expected: "b"},
+				{Path: "[c]", Kind: diff.Extra, Actual: "d"},
+			},
+		},
+		{
+			name:     "value mismatch in nested field",
+			expected: map[string]interface{}{"log": map[string]interface{}{"file": map[string]interface{}{"path": "/var/log/a"}}},
+			actual:   map[string]interface{}{"log": map[string]interface{}{"file": map[string]interface{}{"path": "/var/log/b"}}},
+
+			want: []diff.Difference{
+				{Path: "[log][file][path]", Kind: diff.Value, Expected: "/var/log/a", Actual: "/var/log/b"},
+			},
+		},
+		{
+			name:     "type mismatch",
+			expected: map[string]interface{}{"port": "80"},
+			actual:   map[string]interface{}{"port": 80},
+
+			want: []diff.Difference{
+				{Path: "[port]", Kind: diff.Type, Expected: "80", Actual: 80.0},
+			},
+		},
+		{
+			name:     "array elements",
+			expected: map[string]interface{}{"tags": []interface{}{"a", "b", "c"}},
+			actual:   map[string]interface{}{"tags": []interface{}{"a", "x"}},
+
+			want: []diff.Difference{
+				{Path: "[tags][1]", Kind: diff.Value, Expected: "b", Actual: "x"},
+				{Path: "[tags][2]", Kind: diff.Missing, Expected: "c"},
+			},
+		},
+		{
+			name:     "boolean value mismatch",
+			expected: map[string]interface{}{"a": map[string]interface{}{"b": true}},
+			actual:   map[string]interface{}{"a": map[string]interface{}{"b": false}},
+
+			want: []diff.Difference{
+				{Path: "[a][b]", Kind: diff.Value, Expected: true, Actual: false},
+			},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			got, err := diff.Compare(test.expected, test.actual)
+			is.NoErr(err)
+
+			is.Equal(got, test.want)
+		})
+	}
+}
+
+func TestDifferenceString(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(diff.Difference{Path: "[a]", Kind: diff.Missing, Expected: "b"}.String(), `[a]: missing field, expected "b"`)
+	is.Equal(diff.Difference{Path: "[a]", Kind: diff.Extra, Actual: 1.0}.String(), `[a]: unexpected field with value 1`)
+	is.Equal(diff.Difference{Path: "[a]", Kind: diff.Type, Expected: "1", Actual: 1.0}.String(), `[a]: type mismatch, expected string "1", got number 1`)
+	is.Equal(diff.Difference{Path: "[a]", Kind: diff.Value, Expected: "b", Actual: "c"}.String(), `[a]: value mismatch, expected "b", got "c"`)
+}
+
+func TestSubset(t *testing.T) {
+	is := is.New(t)
+
+	expected := map[string]interface{}{
+		"message": "test",
+		"geoip": map[string]interface{}{
+			"country": "CH",
+		},
+		"id":   map[string]interface{}{"$any": true},
+		"list": []interface{}{map[string]interface{}{"a": 1}},
+	}
+	actual := map[string]interface{}{
+		"message": "test",
+		"geoip": map[string]interface{}{
+			"country": "CH",
+			"city":    "Zurich",
+		},
+		"id":        map[string]interface{}{"value": "x"},
+		"list":      []interface{}{map[string]interface{}{"a": 1, "b": 2}, "extra"},
+		"useragent": "curl",
+	}
+
+	got, err := diff.Subset(expected, actual)
+	is.NoErr(err)
+
+	is.Equal(got, map[string]interface{}{
+		"message": "test",
+		"geoip": map[string]interface{}{
+			"country": "CH",
+		},
+		"id":   map[string]interface{}{"value": "x"},
+		"list": []interface{}{map[string]interface{}{"a": 1.0}, "extra"},
+	})
+}
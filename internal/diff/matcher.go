@@ -0,0 +1,351 @@
+package diff
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+const defaultTolerance = 1e-9
+
+// matchers contains the names of the known matchers.
+var matchers = map[string]bool{
+	"$regex":     true,
+	"$type":      true,
+	"$any":       true,
+	"$contains":  true,
+	"$approx":    true,
+	"$tolerance": true,
+	"$range":     true,
+}
+
+// regexCache contains the compiled regular expressions of the $regex
+// matchers by their pattern, since the same matchers are usually evaluated
+// for many events.
+var regexCache sync.Map
+
+// isMatcher returns true, if v is an object consisting only of the keys of
+// known matchers. Other objects, even if their keys start with $, are
+// compared literally.
+func isMatcher(v interface{}) bool {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return false
+	}
+	for key := range m {
+		if !matchers[key] {
+			return false
+		}
+	}
+	return true
+}
+
+// match evaluates the matcher against actual. If actual does not satisfy
+// the matcher, a description of the reason is returned, otherwise an
+// empty string. A non-nil error value indicates an invalid matcher.
+func match(matcher map[string]interface{}, actual interface{}) (string, error) {
+	keys := make([]string, 0, len(matcher))
+	for key := range matcher {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var (
+			reason string
+			err    error
+		)
+		switch key {
+		case "$regex":
+			reason, err = matchRegex(matcher[key], actual)
+		case "$type":
+			reason, err = matchType(matcher[key], actual)
+		case "$any":
+			reason, err = matchAny(matcher[key], actual)
+		case "$contains":
+			reason, err = matchContains(matcher[key], actual)
+		case "$approx":
+			reason, err = matchApprox(matcher[key], matcher["$tolerance"], actual)
+		case "$tolerance":
+			if _, ok := matcher["$approx"]; !ok {
+				err = fmt.Errorf("matcher $tolerance requires $approx")
+			}
+		case "$range":
+			reason, err = matchRange(matcher[key], actual)
+		}
+		if err != nil {
+			return "", err
+		}
+		if reason != "" {
+			return reason, nil
+		}
+	}
+	return "", nil
+}
+
+func matchRegex(arg, actual interface{}) (string, error) {
+	pattern, ok := arg.(string)
+	if !ok {
+		return "", fmt.Errorf("matcher $regex requires a string, got %s", format(arg))
+	}
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return "", fmt.Errorf("matcher $regex: %s", err)
+	}
+	s, ok := actual.(string)
+	if !ok {
+		return fmt.Sprintf("expected string matching regex %s, got %s %s", format(pattern), typeName(actual), format(actual)), nil
+	}
+	if !re.MatchString(s) {
+		return fmt.Sprintf("%s does not match regex %s", format(s), format(pattern)), nil
+	}
+	return "", nil
+}
+
+// compileRegex returns the compiled regular expression for pattern from
+// regexCache or compiles it.
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexCache.Store(pattern, re)
+	return re, nil
+}
+
+func matchType(arg, actual interface{}) (string, error) {
+	name, ok := arg.(string)
+	if !ok {
+		return "", fmt.Errorf("matcher $type requires a string, got %s", format(arg))
+	}
+	switch name {
+	case "string", "number", "boolean", "object", "array", "null":
+	default:
+		return "", fmt.Errorf("matcher $type: unknown type %q", name)
+	}
+	if typeName(actual) != name {
+		return fmt.Sprintf("expected value of type %s, got %s %s", name, typeName(actual), format(actual)), nil
+	}
+	return "", nil
+}
+
+func matchAny(arg, _ interface{}) (string, error) {
+	if b, ok := arg.(bool); !ok || !b {
+		return "", fmt.Errorf("matcher $any requires the value true, got %s", format(arg))
+	}
+	return "", nil
+}
+
+func matchContains(arg, actual interface{}) (string, error) {
+	wanted, ok := arg.([]interface{})
+	if !ok {
+		wanted = []interface{}{arg}
+	}
+	elements, ok := actual.([]interface{})
+	if !ok {
+		return fmt.Sprintf("expected array containing %s, got %s %s", format(wanted), typeName(actual), format(actual)), nil
+	}
+
+	for _, w := range wanted {
+		found := false
+		for _, element := range elements {
+			var diffs []Difference
+			if err := compare("", w, element, &diffs); err != nil {
+				return "", err
+			}
+			if len(diffs) == 0 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("%s does not contain %s", format(actual), format(w)), nil
+		}
+	}
+	return "", nil
+}
+
+func matchApprox(arg, toleranceArg, actual interface{}) (string, error) {
+	expected, ok := arg.(float64)
+	if !ok {
+		return "", fmt.Errorf("matcher $approx requires a number, got %s", format(arg))
+	}
+	tolerance := defaultTolerance
+	if toleranceArg != nil {
+		tolerance, ok = toleranceArg.(float64)
+		if !ok || tolerance < 0 {
+			return "", fmt.Errorf("matcher $tolerance requires a non-negative number, got %s", format(toleranceArg))
+		}
+	}
+	n, ok := actual.(float64)
+	if !ok {
+		return fmt.Sprintf("expected number, got %s %s", typeName(actual), format(actual)), nil
+	}
+	if math.Abs(n-expected) > tolerance {
+		return fmt.Sprintf("%s is not within %s of %s", format(n), format(tolerance), format(expected)), nil
+	}
+	return "", nil
+}
+
+func matchRange(arg, actual interface{}) (string, error) {
+	bounds, ok := arg.([]interface{})
+	if !ok || len(bounds) != 2 {
+		return "", fmt.Errorf("matcher $range requires an array [min, max], got %s", format(arg))
+	}
+	lower, lowerOK := bounds[0].(float64)
+	upper, upperOK := bounds[1].(float64)
+	if !lowerOK || !upperOK || lower > upper {
+		return "", fmt.Errorf("matcher $range requires an array [min, max] of numbers, got %s", format(arg))
+	}
+	n, ok := actual.(float64)
+	if !ok {
+		return fmt.Sprintf("expected number, got %s %s", typeName(actual), format(actual)), nil
+	}
+	if n < lower || n > upper {
+		return fmt.Sprintf("%s is not within range %s", format(n), format(bounds)), nil
+	}
+	return "", nil
+}
+
+// Resolve returns a copy of expected, where all matchers, which are
+// satisfied by the respective value in actual, are replaced by that value.
+// Matchers, which are not satisfied, are kept as is. This allows to use
+// expected values containing matchers with a textual diff command.
+func Resolve(expected, actual interface{}) (interface{}, error) {
+	normalizedExpected, err := normalize(expected)
+	if err != nil {
+		return nil, err
+	}
+	normalizedActual, err := normalize(actual)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolve(normalizedExpected, normalizedActual)
+}
+
+func resolve(expected, actual interface{}) (interface{}, error) {
+	if isMatcher(expected) {
+		reason, err := match(expected.(map[string]interface{}), actual)
+		if err != nil {
+			return nil, err
+		}
+		if reason == "" {
+			return actual, nil
+		}
+		return expected, nil
+	}
+
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualValue, ok := actual.(map[string]interface{})
+		if !ok {
+			return expected, nil
+		}
+		resolved := make(map[string]interface{}, len(expectedValue))
+		for key, e := range expectedValue {
+			resolved[key] = e
+			if a, ok := actualValue[key]; ok {
+				r, err := resolve(e, a)
+				if err != nil {
+					return nil, err
+				}
+				resolved[key] = r
+			}
+		}
+		return resolved, nil
+	case []interface{}:
+		actualValue, ok := actual.([]interface{})
+		if !ok {
+			return expected, nil
+		}
+		resolved := make([]interface{}, len(expectedValue))
+		for i, e := range expectedValue {
+			resolved[i] = e
+			if i < len(actualValue) {
+				r, err := resolve(e, actualValue[i])
+				if err != nil {
+					return nil, err
+				}
+				resolved[i] = r
+			}
+		}
+		return resolved, nil
+	default:
+		return expected, nil
+	}
+}
+
+// Preserve returns a copy of actual, where all values, which satisfy the
+// respective matcher in expected, are replaced by the matcher. This is the
+// inverse operation of Resolve and allows to update expected values with
+// actual values without losing the matchers, which are still satisfied.
+func Preserve(expected, actual interface{}) (interface{}, error) {
+	normalizedExpected, err := normalize(expected)
+	if err != nil {
+		return nil, err
+	}
+	normalizedActual, err := normalize(actual)
+	if err != nil {
+		return nil, err
+	}
+
+	return preserve(normalizedExpected, normalizedActual)
+}
+
+func preserve(expected, actual interface{}) (interface{}, error) {
+	if isMatcher(expected) {
+		reason, err := match(expected.(map[string]interface{}), actual)
+		if err != nil {
+			return nil, err
+		}
+		if reason == "" {
+			return expected, nil
+		}
+		return actual, nil
+	}
+
+	switch actualValue := actual.(type) {
+	case map[string]interface{}:
+		expectedValue, ok := expected.(map[string]interface{})
+		if !ok {
+			return actual, nil
+		}
+		preserved := make(map[string]interface{}, len(actualValue))
+		for key, a := range actualValue {
+			preserved[key] = a
+			if e, ok := expectedValue[key]; ok {
+				p, err := preserve(e, a)
+				if err != nil {
+					return nil, err
+				}
+				preserved[key] = p
+			}
+		}
+		return preserved, nil
+	case []interface{}:
+		expectedValue, ok := expected.([]interface{})
+		if !ok {
+			return actual, nil
+		}
+		preserved := make([]interface{}, len(actualValue))
+		for i, a := range actualValue {
+			preserved[i] = a
+			if i < len(expectedValue) {
+				p, err := preserve(expectedValue[i], a)
+				if err != nil {
+					return nil, err
+				}
+				preserved[i] = p
+			}
+		}
+		return preserved, nil
+	default:
+		return actual, nil
+	}
+}
@@ -0,0 +1,226 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/diff"
+)
+
+func TestCompareMatcher(t *testing.T) {
+	cases := []struct {
+		name    string
+		matcher map[string]interface{}
+		actual  interface{}
+
+		wantMatch bool
+		wantErr   bool
+	}{
+		{
+			name:      "regex match",
+			matcher:   map[string]interface{}{"$regex": `^\d{4}-`},
+			actual:    "2021-01-01T00:00:00.000Z",
+			wantMatch: true,
+		},
+		{
+			name:    "regex no match",
+			matcher: map[string]interface{}{"$regex": `^\d{4}-`},
+			actual:  "today",
+		},
+		{
+			name:    "regex on number",
+			matcher: map[string]interface{}{"$regex": `^\d+$`},
+			actual:  10,
+		},
+		{
+			name:    "invalid regex",
+			matcher: map[string]interface{}{"$regex": `(`},
+			actual:  "test",
+			wantErr: true,
+		},
+		{
+			name:      "type match",
+			matcher:   map[string]interface{}{"$type": "number"},
+			actual:    10,
+			wantMatch: true,
+		},
+		{
+			name:    "type no match",
+			matcher: map[string]interface{}{"$type": "number"},
+			actual:  "10",
+		},
+		{
+			name:    "unknown type",
+			matcher: map[string]interface{}{"$type": "integer"},
+			actual:  10,
+			wantErr: true,
+		},
+		{
+			name:      "any",
+			matcher:   map[string]interface{}{"$any": true},
+			actual:    map[string]interface{}{"a": "b"},
+			wantMatch: true,
+		},
+		{
+			name:      "contains",
+			matcher:   map[string]interface{}{"$contains": []interface{}{"b", map[string]interface{}{"c": 1}}},
+			actual:    []interface{}{"a", "b", map[string]interface{}{"c": 1}},
+			wantMatch: true,
+		},
+		{
+			name:      "contains single value",
+			matcher:   map[string]interface{}{"$contains": "b"},
+			actual:    []interface{}{"a", "b"},
+			wantMatch: true,
+		},
+		{
+			name:    "contains no match",
+			matcher: map[string]interface{}{"$contains": []interface{}{"c"}},
+			actual:  []interface{}{"a", "b"},
+		},
+		{
+			name:      "contains with nested matcher",
+			matcher:   map[string]interface{}{"$contains": map[string]interface{}{"$regex": "^b"}},
+			actual:    []interface{}{"a", "bc"},
+			wantMatch: true,
+		},
+		{
+			name:      "approx",
+			matcher:   map[string]interface{}{"$approx": 3.14, "$tolerance": 0.01},
+			actual:    3.141,
+			wantMatch: true,
+		},
+		{
+			name:    "approx no match",
+			matcher: map[string]interface{}{"$approx": 3.14},
+			actual:  3.141,
+		},
+		{
+			name:    "tolerance without approx",
+			matcher: map[string]interface{}{"$tolerance": 0.01},
+			actual:  3.141,
+			wantErr: true,
+		},
+		{
+			name:      "range",
+			matcher:   map[string]interface{}{"$range": []interface{}{0, 100}},
+			actual:    100,
+			wantMatch: true,
+		},
+		{
+			name:    "range no match",
+			matcher: map[string]interface{}{"$range": []interface{}{0, 100}},
+			actual:  101,
+		},
+		{
+			name:      "combined",
+			matcher:   map[string]interface{}{"$type": "string", "$regex": "^a"},
+			actual:    "abc",
+			wantMatch: true,
+		},
+		{
+			name:      "unknown matcher compared literally",
+			matcher:   map[string]interface{}{"$foo": "bar"},
+			actual:    map[string]interface{}{"$foo": "bar"},
+			wantMatch: true,
+		},
+		{
+			name:      "matcher key mixed with other key compared literally",
+			matcher:   map[string]interface{}{"$type": "string", "value": "a"},
+			actual:    map[string]interface{}{"$type": "string", "value": "a"},
+			wantMatch: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			expected := map[string]interface{}{"field": test.matcher}
+			actual := map[string]interface{}{"field": test.actual}
+
+			got, err := diff.Compare(expected, actual)
+			is.Equal(err != nil, test.wantErr) // error
+			if test.wantErr {
+				return
+			}
+
+			is.Equal(len(got) == 0, test.wantMatch) // match
+			if !test.wantMatch {
+				is.Equal(got[0].Kind, diff.Match)
+				is.Equal(got[0].Path, "[field]")
+				is.True(got[0].Message != "")
+			}
+		})
+	}
+}
+
+func TestCompareMatcherMissingField(t *testing.T) {
+	is := is.New(t)
+
+	got, err := diff.Compare(map[string]interface{}{"field": map[string]interface{}{"$any": true}}, map[string]interface{}{})
+	is.NoErr(err)
+
+	is.Equal(len(got), 1)
+	is.Equal(got[0].Kind, diff.Missing)
+}
+
+func TestResolve(t *testing.T) {
+	is := is.New(t)
+
+	expected := map[string]interface{}{
+		"message": "test",
+		"id":      map[string]interface{}{"$regex": "^[0-9a-f]+$"},
+		"host":    map[string]interface{}{"$type": "number"},
+		"nested": map[string]interface{}{
+			"list": []interface{}{map[string]interface{}{"$any": true}},
+		},
+	}
+	actual := map[string]interface{}{
+		"message": "test",
+		"id":      "c0ffee",
+		"host":    "localhost",
+		"nested": map[string]interface{}{
+			"list": []interface{}{"x"},
+		},
+	}
+
+	got, err := diff.Resolve(expected, actual)
+	is.NoErr(err)
+
+	is.Equal(got, map[string]interface{}{
+		"message": "test",
+		"id":      "c0ffee",
+		"host":    map[string]interface{}{"$type": "number"},
+		"nested": map[string]interface{}{
+			"list": []interface{}{"x"},
+		},
+	})
+}
+
+func TestPreserve(t *testing.T) {
+	is := is.New(t)
+
+	expected := map[string]interface{}{
+		"message": "old",
+		"id":      map[string]interface{}{"$regex": "^[0-9a-f]+$"},
+		"host":    map[string]interface{}{"$type": "number"},
+	}
+	actual := map[string]interface{}{
+		"message": "new",
+		"id":      "c0ffee",
+		"host":    "localhost",
+		"extra":   true,
+	}
+
+	got, err := diff.Preserve(expected, actual)
+	is.NoErr(err)
+
+	is.Equal(got, map[string]interface{}{
+		"message": "new",
+		"id":      map[string]interface{}{"$regex": "^[0-9a-f]+$"},
+		"host":    "localhost",
+		"extra":   true,
+	})
+}
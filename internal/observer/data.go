@@ -1,5 +1,9 @@
 package observer
 
+import (
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/diff"
+)
+
 // TestExecutionStart is empty struct to inform consumer that test execution has begun.
 type TestExecutionStart struct{}
 
@@ -8,11 +12,28 @@ type TestExecutionEnd struct{}
 
 // ComparisonResult describes the result of the execution of a single test case.
 type ComparisonResult struct {
-	Name       string
-	Status     bool
-	Explain    string
-	Path       string
-	EventIndex int
+	Name        string
+	Status      bool
+	Explain     string
+	Path        string
+	EventIndex  int
+	Description string
+
+	// Expected and Actual contain the compared events. If the number of
+	// expected and actual events differ, they contain all the events of
+	// the test case file.
+	Expected interface{}
+	Actual   interface{}
+
+	// Differences contains the field level differences between the
+	// expected and the actual event, if the events have been compared
+	// with the builtin comparator.
+	Differences []diff.Difference
+
+	// Trace contains the step by step changes of the filters to the
+	// actual event, if the test cases have been executed with tracing
+	// enabled.
+	Trace string
 }
 
 // Interface defines the methods of an observer.
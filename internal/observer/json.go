@@ -0,0 +1,130 @@
+package observer
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/imkira/go-observer"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/diff"
+)
+
+// JSONObserver implements an LFV event observer that writes each
+// comparison result as a JSON object on a single line (NDJSON) as soon
+// as it is received, followed by a summary object at the end of the
+// test execution.
+type JSONObserver struct {
+	done chan error
+	prop observer.Property
+	out  io.Writer
+}
+
+const (
+	statusPassed = "passed"
+	statusFailed = "failed"
+)
+
+type jsonResult struct {
+	Type        string      `json:"type"`
+	Path        string      `json:"path"`
+	EventIndex  int         `json:"event_index"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Status      string      `json:"status"`
+	Expected    interface{} `json:"expected"`
+	Actual      interface{} `json:"actual"`
+	Diff        string      `json:"diff,omitempty"`
+	Trace       string      `json:"trace,omitempty"`
+
+	Differences []diff.Difference `json:"differences,omitempty"`
+}
+
+type jsonSummary struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	Passed int    `json:"passed"`
+	Failed int    `json:"failed"`
+	Total  int    `json:"total"`
+}
+
+// NewJSONObserver initializes a new JSONObserver struct, which writes
+// the results to out.
+func NewJSONObserver(prop observer.Property, out io.Writer) *JSONObserver {
+	return &JSONObserver{
+		done: make(chan error),
+		prop: prop,
+		out:  out,
+	}
+}
+
+// Start launches a consumer responsible for writing the comparison
+// results as they arrive.
+func (jo *JSONObserver) Start() error {
+	stream := jo.prop.Observe()
+
+	go func() {
+		var (
+			summary jsonSummary
+			err     error
+		)
+
+		enc := json.NewEncoder(jo.out)
+
+		for {
+			data := stream.Value()
+
+			switch event := data.(type) {
+			case TestExecutionStart:
+				summary = jsonSummary{
+					Type: "summary",
+				}
+				err = nil
+			case TestExecutionEnd:
+				summary.Total = summary.Passed + summary.Failed
+				summary.Status = statusPassed
+				if summary.Failed > 0 {
+					summary.Status = statusFailed
+				}
+				if err == nil {
+					err = enc.Encode(summary)
+				}
+				jo.done <- err
+			case ComparisonResult:
+				result := jsonResult{
+					Type:        "result",
+					Path:        event.Path,
+					EventIndex:  event.EventIndex,
+					Name:        event.Name,
+					Description: event.Description,
+					Status:      statusPassed,
+					Expected:    event.Expected,
+					Actual:      event.Actual,
+				}
+				if event.Status {
+					summary.Passed++
+				} else {
+					summary.Failed++
+					result.Status = statusFailed
+					result.Diff = event.Explain
+					result.Differences = event.Differences
+					result.Trace = event.Trace
+				}
+				if err == nil {
+					err = enc.Encode(result)
+				}
+			default:
+				log.Debugf("Receive data that we doesn't say how to manage it %+v", data)
+			}
+
+			<-stream.Changes()
+			stream.Next()
+		}
+	}()
+	return nil
+}
+
+// Finalize waits for the observer to receive the final property value
+// and to write the summary.
+func (jo *JSONObserver) Finalize() error {
+	return <-jo.done
+}
@@ -0,0 +1,70 @@
+package observer_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/imkira/go-observer"
+	"github.com/matryer/is"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/diff"
+	lfvobserver "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/observer"
+)
+
+func TestJSONObserver(t *testing.T) {
+	cases := []struct {
+		name    string
+		results []lfvobserver.ComparisonResult
+
+		want string
+	}{
+		{
+			name: "no results",
+
+			want: `{"type":"summary","status":"passed","passed":0,"failed":0,"total":0}
+`,
+		},
+		{
+			name: "passed and failed",
+			results: []lfvobserver.ComparisonResult{
+				{
+					Name:        "Comparing message 1 of 2 from syslog.json",
+					Status:      true,
+					Path:        "team-a/syslog.json",
+					Description: "parse message",
+					Expected:    map[string]interface{}{"message": "a"},
+					Actual:      map[string]interface{}{"message": "a"},
+				},
+				{
+					Name:        "Comparing message 2 of 2 from syslog.json",
+					Status:      false,
+					Path:        "team-a/syslog.json",
+					EventIndex:  1,
+					Explain:     "--- expected\n+++ actual",
+					Expected:    map[string]interface{}{"message": "b"},
+					Actual:      map[string]interface{}{"message": "c"},
+					Differences: []diff.Difference{{Path: "[message]", Kind: diff.Value, Expected: "b", Actual: "c"}},
+					Trace:       "mutate: changed [message]",
+				},
+			},
+
+			want: `{"type":"result","path":"team-a/syslog.json","event_index":0,"name":"Comparing message 1 of 2 from syslog.json","description":"parse message","status":"passed","expected":{"message":"a"},"actual":{"message":"a"}}
+{"type":"result","path":"team-a/syslog.json","event_index":1,"name":"Comparing message 2 of 2 from syslog.json","status":"failed","expected":{"message":"b"},"actual":{"message":"c"},"diff":"--- expected\n+++ actual","trace":"mutate: changed [message]","differences":[{"path":"[message]","kind":"value","expected":"b","actual":"c"}]}
+{"type":"summary","status":"failed","passed":1,"failed":1,"total":2}
+`,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			out := &bytes.Buffer{}
+			runObserver(t, func(prop observer.Property) lfvobserver.Interface {
+				return lfvobserver.NewJSONObserver(prop, out)
+			}, test.results)
+
+			is.Equal(out.String(), test.want)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package observer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/imkira/go-observer"
+)
+
+// JUnitObserver implements an LFV event observer that writes the results
+// of the test execution as JUnit XML report to a file when it's over.
+// Each test case file is reported as a testsuite, each compared event
+// as a testcase within the respective testsuite.
+type JUnitObserver struct {
+	done     chan error
+	prop     observer.Property
+	filename string
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// NewJUnitObserver initializes a new JUnitObserver struct, which writes
+// the report to filename.
+func NewJUnitObserver(prop observer.Property, filename string) *JUnitObserver {
+	return &JUnitObserver{
+		done:     make(chan error),
+		prop:     prop,
+		filename: filename,
+	}
+}
+
+// Start launches a consumer responsible for collecting the test results
+// and writing the JUnit report at the end of the execution.
+func (jo *JUnitObserver) Start() error {
+	stream := jo.prop.Observe()
+
+	go func() {
+		var suites map[string]*junitTestSuite
+
+		for {
+			data := stream.Value()
+
+			switch event := data.(type) {
+			case TestExecutionStart:
+				suites = make(map[string]*junitTestSuite)
+			case TestExecutionEnd:
+				jo.done <- jo.write(suites)
+			case ComparisonResult:
+				suite, ok := suites[event.Path]
+				if !ok {
+					suite = &junitTestSuite{
+						Name: event.Path,
+					}
+					suites[event.Path] = suite
+				}
+
+				testcase := junitTestCase{
+					Name:      event.Name,
+					Classname: event.Path,
+				}
+				if !event.Status {
+					testcase.Failure = &junitFailure{
+						Message: fmt.Sprintf("%s failed", event.Name),
+						Content: event.Explain,
+					}
+					if event.Trace != "" {
+						testcase.Failure.Content += "\nFilter trace:\n" + event.Trace
+					}
+					suite.Failures++
+				}
+				suite.Tests++
+				suite.TestCases = append(suite.TestCases, testcase)
+			default:
+				log.Debugf("Receive data that we doesn't say how to manage it %+v", data)
+			}
+
+			<-stream.Changes()
+			stream.Next()
+		}
+	}()
+	return nil
+}
+
+// Finalize waits for the observer to receive the final property value
+// and to write the JUnit report.
+func (jo *JUnitObserver) Finalize() error {
+	return <-jo.done
+}
+
+func (jo *JUnitObserver) write(suites map[string]*junitTestSuite) error {
+	report := junitTestSuites{}
+
+	// Ordering by keys name
+	keys := make([]string, 0, len(suites))
+	for key := range suites {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		suite := suites[key]
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Suites = append(report.Suites, *suite)
+	}
+
+	body, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JUnit report: %s", err)
+	}
+
+	body = append([]byte(xml.Header), body...)
+	body = append(body, '\n')
+	if err := os.WriteFile(jo.filename, body, 0644); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package observer_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/imkira/go-observer"
+	"github.com/matryer/is"
+
+	lfvobserver "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/observer"
+)
+
+// runObserver feeds the results as a complete test execution to the
+// observer created by newObserver.
+func runObserver(t *testing.T, newObserver func(prop observer.Property) lfvobserver.Interface, results []lfvobserver.ComparisonResult) {
+	t.Helper()
+	is := is.New(t)
+
+	prop := observer.NewProperty(lfvobserver.TestExecutionStart{})
+	o := newObserver(prop)
+	is.NoErr(o.Start())
+	for _, result := range results {
+		prop.Update(result)
+	}
+	prop.Update(lfvobserver.TestExecutionEnd{})
+	is.NoErr(o.Finalize())
+}
+
+func TestJUnitObserver(t *testing.T) {
+	cases := []struct {
+		name    string
+		results []lfvobserver.ComparisonResult
+
+		want string
+	}{
+		{
+			name: "no results",
+
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="0" failures="0"></testsuites>
+`,
+		},
+		{
+			name: "suites ordered by path",
+			results: []lfvobserver.ComparisonResult{
+				{Name: "Comparing message 1 of 2 from syslog.json", Status: true, Path: "team-b/syslog.json"},
+				{Name: "Comparing message 2 of 2 from syslog.json", Status: false, Path: "team-b/syslog.json", Explain: "--- expected\n+++ actual"},
+				{Name: "Comparing message 1 of 1 from beats.yml", Status: true, Path: "team-a/beats.yml"},
+			},
+
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="3" failures="1">
+  <testsuite name="team-a/beats.yml" tests="1" failures="0">
+    <testcase name="Comparing message 1 of 1 from beats.yml" classname="team-a/beats.yml"></testcase>
+  </testsuite>
+  <testsuite name="team-b/syslog.json" tests="2" failures="1">
+    <testcase name="Comparing message 1 of 2 from syslog.json" classname="team-b/syslog.json"></testcase>
+    <testcase name="Comparing message 2 of 2 from syslog.json" classname="team-b/syslog.json">
+      <failure message="Comparing message 2 of 2 from syslog.json failed">--- expected&#xA;+++ actual</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+		{
+			name: "escaping",
+			results: []lfvobserver.ComparisonResult{
+				{Name: `"<a> & <b>"`, Status: false, Path: "a&b.json", Explain: `-"<tag>" & "x"`, Trace: "grok: added [tags] <none>"},
+			},
+
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="1" failures="1">
+  <testsuite name="a&amp;b.json" tests="1" failures="1">
+    <testcase name="&#34;&lt;a&gt; &amp; &lt;b&gt;&#34;" classname="a&amp;b.json">
+      <failure message="&#34;&lt;a&gt; &amp; &lt;b&gt;&#34; failed">-&#34;&lt;tag&gt;&#34; &amp; &#34;x&#34;&#xA;Filter trace:&#xA;grok: added [tags] &lt;none&gt;</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			filename := filepath.Join(t.TempDir(), "report.xml")
+			runObserver(t, func(prop observer.Property) lfvobserver.Interface {
+				return lfvobserver.NewJUnitObserver(prop, filename)
+			}, test.results)
+
+			body, err := os.ReadFile(filename)
+			is.NoErr(err)
+			is.Equal(string(body), test.want)
+		})
+	}
+}
+
+func TestJUnitObserverWriteError(t *testing.T) {
+	is := is.New(t)
+
+	prop := observer.NewProperty(lfvobserver.TestExecutionStart{})
+	o := lfvobserver.NewJUnitObserver(prop, filepath.Join(t.TempDir(), "missing", "report.xml"))
+	is.NoErr(o.Start())
+	prop.Update(lfvobserver.TestExecutionEnd{})
+	is.True(o.Finalize() != nil) // report directory does not exist
+}
@@ -0,0 +1,49 @@
+package observer
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/imkira/go-observer"
+)
+
+// Output formats supported by NewOutputObserver.
+const (
+	OutputFormatText = "text"
+	OutputFormatJSON = "json"
+)
+
+// NewOutputObserver creates the observer, which presents the test results
+// on stdout in the given output format.
+func NewOutputObserver(prop observer.Property, outputFormat string) (Interface, error) {
+	switch outputFormat {
+	case OutputFormatText:
+		return NewSummaryObserver(prop), nil
+	case OutputFormatJSON:
+		return NewJSONObserver(prop, os.Stdout), nil
+	default:
+		return nil, fmt.Errorf("unsupported output format %q, supported formats: %s, %s", outputFormat, OutputFormatText, OutputFormatJSON)
+	}
+}
+
+// NewReportObservers creates an observer for each of the given report
+// definitions. A report definition has the form <type>:<path>, e.g.
+// junit:report.xml.
+func NewReportObservers(prop observer.Property, reports []string) ([]Interface, error) {
+	observers := make([]Interface, 0, len(reports))
+	for _, report := range reports {
+		reportType, path, ok := strings.Cut(report, ":")
+		if !ok || path == "" {
+			return nil, fmt.Errorf("invalid report %q, expected format <type>:<path>", report)
+		}
+
+		switch reportType {
+		case "junit":
+			observers = append(observers, NewJUnitObserver(prop, path))
+		default:
+			return nil, fmt.Errorf("unsupported report type %q in report %q", reportType, report)
+		}
+	}
+	return observers, nil
+}
@@ -0,0 +1,78 @@
+package observer_test
+
+import (
+	"testing"
+
+	"github.com/imkira/go-observer"
+	"github.com/matryer/is"
+
+	lfvobserver "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/observer"
+)
+
+func TestNewReportObservers(t *testing.T) {
+	cases := []struct {
+		name    string
+		reports []string
+
+		wantCount int
+		wantErr   bool
+	}{
+		{
+			name: "no reports",
+		},
+		{
+			name:    "junit",
+			reports: []string{"junit:report.xml", "junit:reports/other.xml"},
+
+			wantCount: 2,
+		},
+		{
+			name:    "path with colon",
+			reports: []string{"junit:C:/reports/report.xml"},
+
+			wantCount: 1,
+		},
+		{
+			name:    "missing path",
+			reports: []string{"junit"},
+
+			wantErr: true,
+		},
+		{
+			name:    "empty path",
+			reports: []string{"junit:"},
+
+			wantErr: true,
+		},
+		{
+			name:    "unsupported type",
+			reports: []string{"xunit:report.xml"},
+
+			wantErr: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			observers, err := lfvobserver.NewReportObservers(observer.NewProperty(nil), test.reports)
+			is.Equal(err != nil, test.wantErr) // error
+			if test.wantErr {
+				return
+			}
+			is.Equal(len(observers), test.wantCount)
+		})
+	}
+}
+
+func TestNewOutputObserver(t *testing.T) {
+	is := is.New(t)
+
+	_, err := lfvobserver.NewOutputObserver(observer.NewProperty(nil), lfvobserver.OutputFormatText)
+	is.NoErr(err)
+	_, err = lfvobserver.NewOutputObserver(observer.NewProperty(nil), lfvobserver.OutputFormatJSON)
+	is.NoErr(err)
+	_, err = lfvobserver.NewOutputObserver(observer.NewProperty(nil), "xml")
+	is.True(err != nil) // unsupported output format
+}
@@ -84,6 +84,9 @@ func (so *SummaryObserver) Start() error {
 					summary.NumberNotOk++
 					globalSummary.NumberNotOk++
 					fmt.Printf("\u2610 %s from %s:\n%s\n", event.Name, event.Path, event.Explain)
+					if event.Trace != "" {
+						fmt.Printf("Filter trace:\n%s\n", event.Trace)
+					}
 				}
 				results[event.Path] = summary
 			default:
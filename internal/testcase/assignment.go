@@ -0,0 +1,76 @@
+package testcase
+
+import (
+	"math"
+)
+
+// minCostAssignment solves the assignment problem for the given square
+// cost matrix with the Hungarian algorithm. It returns for each row the
+// index of the column assigned to it, such that the sum of the costs of
+// all assigned cells is minimal.
+func minCostAssignment(cost [][]int) []int {
+	const inf = math.MaxInt / 2
+
+	n := len(cost)
+
+	// The algorithm uses 1-based indices, index 0 is used as sentinel.
+	u := make([]int, n+1)
+	v := make([]int, n+1)
+	p := make([]int, n+1)
+	way := make([]int, n+1)
+
+	for i := 1; i <= n; i++ {
+		p[0] = i
+		j0 := 0
+		minv := make([]int, n+1)
+		for j := range minv {
+			minv[j] = inf
+		}
+		used := make([]bool, n+1)
+
+		for {
+			used[j0] = true
+			i0 := p[j0]
+			delta := inf
+			j1 := 0
+			for j := 1; j <= n; j++ {
+				if used[j] {
+					continue
+				}
+				cur := cost[i0-1][j-1] - u[i0] - v[j]
+				if cur < minv[j] {
+					minv[j] = cur
+					way[j] = j0
+				}
+				if minv[j] < delta {
+					delta = minv[j]
+					j1 = j
+				}
+			}
+			for j := 0; j <= n; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+			if p[j0] == 0 {
+				break
+			}
+		}
+
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+
+	assignment := make([]int, n)
+	for j := 1; j <= n; j++ {
+		assignment[p[j]-1] = j - 1
+	}
+	return assignment
+}
@@ -3,44 +3,103 @@
 package testcase
 
 import (
+	"bufio"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/bmatcuk/doublestar/v2"
 )
 
+// IgnoreFile is the name of the file in the test case directory, which
+// contains additional exclude patterns, one pattern per line. Like in a
+// .gitignore file, patterns without a slash match at any depth.
+const IgnoreFile = ".lfvignore"
+
+// DefaultIncludePatterns contains the patterns of the test case files,
+// which are discovered, if no include patterns are given. The patterns
+// only match in the test case directory itself, the subdirectories are
+// included with patterns like "**/*.json".
+var DefaultIncludePatterns = []string{"*.json", "*.yaml", "*.yml"}
+
 // DiscoverTests reads a test case JSON file or YAML file and returns a slice of
 // TestCaseSet structs or, if the input path is a directory, reads all
-// .json or .yaml or .yml files in that directorory and returns them as TestCaseSet
-// structs.
-func DiscoverTests(path string) ([]TestCaseSet, error) {
+// files in that directory and its subdirectories, which match at least one
+// of the include patterns and none of the exclude patterns, and returns them
+// as TestCaseSet structs. The patterns are doublestar globs (e.g.
+// "**/*.json") and are matched against the path relative to the directory.
+// If include is empty, DefaultIncludePatterns are used. The patterns in the
+// IgnoreFile in the directory are added to the exclude patterns.
+func DiscoverTests(path string, include []string, exclude []string) ([]TestCaseSet, error) {
 	pathinfo, err := os.Stat(path)
 	if err != nil {
 		return nil, err
 	}
 
 	if pathinfo.IsDir() {
-		return discoverTestDirectory(path)
+		return discoverTestDirectory(path, include, exclude)
 	}
 	return discoverTestFile(path)
 }
 
-func discoverTestDirectory(path string) ([]TestCaseSet, error) {
-	files, err := os.ReadDir(path)
+func discoverTestDirectory(path string, include []string, exclude []string) ([]TestCaseSet, error) {
+	if len(include) == 0 {
+		include = DefaultIncludePatterns
+	}
+	ignored, err := readIgnoreFile(filepath.Join(path, IgnoreFile))
 	if err != nil {
 		return nil, fmt.Errorf("Error discovering test case files: %s", err)
 	}
-	result := make([]TestCaseSet, 0, len(files))
-	for _, f := range files {
-		if f.IsDir() || (!strings.HasSuffix(f.Name(), ".json") && !strings.HasSuffix(f.Name(), ".yaml") && !strings.HasSuffix(f.Name(), ".yml")) {
-			continue
+	exclude = append(append([]string{}, exclude...), ignored...)
+
+	var files []string
+	err = filepath.WalkDir(path, func(fullpath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if fullpath == path {
+			return nil
+		}
+		rel, err := filepath.Rel(path, fullpath)
+		if err != nil {
+			return err
 		}
-		fullpath := filepath.Join(path, f.Name())
+		rel = filepath.ToSlash(rel)
 
-		tcs, err := NewFromFile(fullpath)
+		excluded, err := matchAny(exclude, rel)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if excluded {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if excluded {
+			return nil
+		}
+		included, err := matchAny(include, rel)
+		if err != nil || !included {
+			return err
+		}
+
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error discovering test case files: %s", err)
+	}
+
+	result := make([]TestCaseSet, 0, len(files))
+	for _, rel := range files {
+		tcs, err := NewFromFile(filepath.Join(path, filepath.FromSlash(rel)))
 		if err != nil {
 			return nil, err
 		}
+		tcs.name = rel
 		result = append(result, *tcs)
 	}
 	return result, nil
@@ -53,3 +112,54 @@ func discoverTestFile(path string) ([]TestCaseSet, error) {
 	}
 	return []TestCaseSet{*tcs}, nil
 }
+
+// matchAny returns true, if the slash separated path matches at least one
+// of the patterns.
+func matchAny(patterns []string, path string) (bool, error) {
+	for _, pattern := range patterns {
+		match, err := doublestar.Match(pattern, path)
+		if err != nil {
+			return false, fmt.Errorf("invalid pattern %q: %s", pattern, err)
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// readIgnoreFile returns the patterns contained in the ignore file. Empty
+// lines and lines starting with # are skipped. A missing ignore file is
+// not an error. Patterns without a slash (except a trailing one) are
+// prefixed with **/, such that they match at any depth, a leading slash
+// anchors the pattern at the test case directory.
+func readIgnoreFile(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSuffix(line, "/")
+		switch {
+		case strings.HasPrefix(line, "/"):
+			line = strings.TrimPrefix(line, "/")
+		case !strings.Contains(line, "/"):
+			line = "**/" + line
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, scanner.Err()
+}
@@ -68,7 +68,7 @@ func TestDiscoverTests_Directory(t *testing.T) {
 			}
 		}
 
-		testcases, err := DiscoverTests(tempdir)
+		testcases, err := DiscoverTests(tempdir, nil, nil)
 		if err != nil {
 			t.Errorf("Test %d: DiscoverTests() unexpectedly returned an error: %s", cnum, err)
 			break
@@ -114,7 +114,7 @@ func TestDiscoverTests_File(t *testing.T) {
 			t.Fatal(err.Error())
 		}
 
-		testcases, err := DiscoverTests(inputpath)
+		testcases, err := DiscoverTests(inputpath, nil, nil)
 		if err != nil {
 			t.Fatalf("DiscoverTests() unexpectedly returned an error: %s", err)
 		}
@@ -128,3 +128,93 @@ func TestDiscoverTests_File(t *testing.T) {
 		}
 	}
 }
+
+// TestDiscoverTests_Patterns tests the include and exclude patterns as
+// well as the ignore file with nested directories.
+func TestDiscoverTests_Patterns(t *testing.T) {
+	files := []string{
+		"top.json",
+		"team-a/syslog.json",
+		"team-a/beats.yml",
+		"team-a/mocks/mock.yml",
+		"team-b/nested/deep/test.yaml",
+		"team-b/nested/notes.txt",
+	}
+
+	cases := []struct {
+		include []string
+		exclude []string
+		ignore  string
+
+		expected []string
+	}{
+		{
+			expected: []string{"top.json"},
+		},
+		{
+			include:  []string{"**/*.json", "**/*.yml", "**/*.yaml"},
+			expected: []string{"top.json", "team-a/syslog.json", "team-a/beats.yml", "team-a/mocks/mock.yml", "team-b/nested/deep/test.yaml"},
+		},
+		{
+			include:  []string{"team-a/*"},
+			expected: []string{"team-a/syslog.json", "team-a/beats.yml"},
+		},
+		{
+			include:  []string{"**/*.{json,yml,yaml}"},
+			exclude:  []string{"**/mocks", "team-b/**"},
+			expected: []string{"top.json", "team-a/syslog.json", "team-a/beats.yml"},
+		},
+		{
+			include:  []string{"**/*.{json,yml,yaml}"},
+			ignore:   "# Plugin mocks\n\nmocks/\nteam-b\n/*.json\n",
+			expected: []string{"team-a/syslog.json", "team-a/beats.yml"},
+		},
+		{
+			include:  []string{"**/*.{json,yml,yaml}"},
+			ignore:   "*.json\nnested/deep\n",
+			expected: []string{"team-a/beats.yml", "team-a/mocks/mock.yml", "team-b/nested/deep/test.yaml"},
+		},
+	}
+	for cnum, c := range cases {
+		tempdir := t.TempDir()
+		for _, f := range files {
+			if err := os.MkdirAll(filepath.Join(tempdir, filepath.Dir(f)), 0755); err != nil {
+				t.Fatal(err.Error())
+			}
+			if err := os.WriteFile(filepath.Join(tempdir, f), []byte(`{"type": "test"}`), 0600); err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+		if c.ignore != "" {
+			if err := os.WriteFile(filepath.Join(tempdir, IgnoreFile), []byte(c.ignore), 0600); err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+
+		testcases, err := DiscoverTests(tempdir, c.include, c.exclude)
+		if err != nil {
+			t.Fatalf("Test %d: DiscoverTests() unexpectedly returned an error: %s", cnum, err)
+		}
+
+		filenames := make([]string, len(testcases))
+		for i, tcs := range testcases {
+			rel, err := filepath.Rel(tempdir, tcs.File)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			filenames[i] = filepath.ToSlash(rel)
+			if tcs.Name() != filenames[i] {
+				t.Errorf("Test %d: Expected name %q for %s, got %q", cnum, filenames[i], tcs.File, tcs.Name())
+			}
+		}
+		sort.Strings(filenames)
+
+		sexpected := make([]string, len(c.expected))
+		copy(sexpected, c.expected)
+		sort.Strings(sexpected)
+
+		if strings.Join(filenames, ",") != strings.Join(sexpected, ",") {
+			t.Errorf("Test %d:\nExpected:\n%v\nGot:\n%v", cnum, sexpected, filenames)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package testcase
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Filter selects the test cases to be executed. The zero value selects
+// all test cases.
+type Filter struct {
+	// Run selects the test cases, whose description or the name of
+	// whose test case file matches the regular expression.
+	Run *regexp.Regexp
+
+	// Tags selects the test cases, which have at least one of the tags.
+	Tags []string
+
+	// SkipTags excludes the test cases, which have at least one of the
+	// tags, even if they are selected by Tags.
+	SkipTags []string
+}
+
+// NewFilter returns a Filter for the regular expression run and the
+// given tags. An empty regular expression matches all test cases.
+func NewFilter(run string, tags []string, skipTags []string) (Filter, error) {
+	f := Filter{
+		Tags:     tags,
+		SkipTags: skipTags,
+	}
+	if run != "" {
+		var err error
+		f.Run, err = regexp.Compile(run)
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid regular expression %q: %s", run, err)
+		}
+	}
+	return f, nil
+}
+
+// IsEmpty returns true, if the filter selects all test cases.
+func (f Filter) IsEmpty() bool {
+	return f.Run == nil && len(f.Tags) == 0 && len(f.SkipTags) == 0
+}
+
+// Apply returns the test case sets reduced to the test cases selected by
+// the filter. Test case sets without any selected test case are omitted.
+func (f Filter) Apply(tests []TestCaseSet) ([]TestCaseSet, error) {
+	if f.IsEmpty() {
+		return tests, nil
+	}
+
+	result := make([]TestCaseSet, 0, len(tests))
+	for _, tcs := range tests {
+		filename := tcs.Name()
+
+		var (
+			testCases []TestCase
+			positions []int
+		)
+		for i, tc := range tcs.TestCases {
+			if !f.selects(filename, tcs.Tags, tc) {
+				continue
+			}
+			testCases = append(testCases, tc)
+			positions = append(positions, tcs.position(i))
+		}
+		if len(testCases) == 0 {
+			continue
+		}
+
+		tcs.TestCases = testCases
+		tcs.positions = positions
+		if err := tcs.prepareTestCases(); err != nil {
+			return nil, err
+		}
+		result = append(result, tcs)
+	}
+	return result, nil
+}
+
+// selects returns true, if the test case tc from the test case file with
+// the given name and tags is selected by the filter.
+func (f Filter) selects(filename string, setTags []string, tc TestCase) bool {
+	if f.Run != nil && !f.Run.MatchString(tc.Description) && !f.Run.MatchString(filename) {
+		return false
+	}
+
+	tags := make(map[string]bool, len(setTags)+len(tc.Tags))
+	for _, tag := range setTags {
+		tags[tag] = true
+	}
+	for _, tag := range tc.Tags {
+		tags[tag] = true
+	}
+
+	for _, tag := range f.SkipTags {
+		if tags[tag] {
+			return false
+		}
+	}
+	if len(f.Tags) == 0 {
+		return true
+	}
+	for _, tag := range f.Tags {
+		if tags[tag] {
+			return true
+		}
+	}
+	return false
+}
+
+// position returns the position of the i-th test case in the test case
+// file.
+func (tcs *TestCaseSet) position(i int) int {
+	if tcs.positions == nil {
+		return i
+	}
+	return tcs.positions[i]
+}
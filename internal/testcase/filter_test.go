@@ -0,0 +1,142 @@
+package testcase
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logstash"
+)
+
+func TestFilterApply(t *testing.T) {
+	syslog := `{
+  "tags": ["syslog"],
+  "testcases": [
+    {"input": ["a"], "expected": [{"message": "a"}], "description": "parse message"},
+    {"input": ["b"], "expected": [{"message": "b"}], "description": "drop debug", "tags": ["slow"]},
+    {"input": ["c"], "expected": [{"message": "c"}], "description": "parse host", "tags": ["wip"]}
+  ]
+}`
+	beats := `{
+  "testcases": [
+    {"input": ["d"], "expected": [{"message": "d"}], "description": "beats message"}
+  ]
+}`
+
+	cases := []struct {
+		name     string
+		run      string
+		tags     []string
+		skipTags []string
+
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "no filter",
+			want: []string{"parse message", "drop debug", "parse host", "beats message"},
+		},
+		{
+			name: "run matches description",
+			run:  "^parse",
+			want: []string{"parse message", "parse host"},
+		},
+		{
+			name: "run matches file name",
+			run:  `beats\.json`,
+			want: []string{"beats message"},
+		},
+		{
+			name: "run matches nothing",
+			run:  "nothing",
+			want: []string{},
+		},
+		{
+			name:    "invalid run",
+			run:     "(",
+			wantErr: true,
+		},
+		{
+			name: "tags of test case set",
+			tags: []string{"syslog"},
+			want: []string{"parse message", "drop debug", "parse host"},
+		},
+		{
+			name: "tags of test case",
+			tags: []string{"slow", "wip"},
+			want: []string{"drop debug", "parse host"},
+		},
+		{
+			name:     "skip tags",
+			skipTags: []string{"slow", "wip"},
+			want:     []string{"parse message", "beats message"},
+		},
+		{
+			name:     "tags and skip tags",
+			run:      "message",
+			tags:     []string{"syslog"},
+			skipTags: []string{"wip"},
+			want:     []string{"parse message"},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			filter, err := NewFilter(test.run, test.tags, test.skipTags)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			var tests []TestCaseSet
+			for _, file := range []struct{ name, content string }{{"syslog.json", syslog}, {"beats.json", beats}} {
+				tcs, err := New(strings.NewReader(file.content), "json")
+				assert.NoError(t, err)
+				tcs.File = file.name
+				tests = append(tests, *tcs)
+			}
+
+			got, err := filter.Apply(tests)
+			assert.NoError(t, err)
+
+			descriptions := []string{}
+			for _, tcs := range got {
+				assert.NotEmpty(t, tcs.TestCases)
+				assert.Len(t, tcs.InputLines, len(tcs.TestCases))
+				assert.Len(t, tcs.Events, len(tcs.TestCases))
+				assert.Len(t, tcs.ExpectedEvents, len(tcs.TestCases))
+				for i, tc := range tcs.TestCases {
+					descriptions = append(descriptions, tc.Description)
+					assert.Equal(t, tc.InputLines[0], tcs.ExpectedEvents[i]["message"])
+				}
+			}
+			assert.Equal(t, test.want, descriptions)
+		})
+	}
+}
+
+func TestFilterUpdateExpected(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.json")
+	err := os.WriteFile(filename, []byte(`{"testcases": [{"input": ["a"]}, {"input": ["b"], "tags": ["wip"]}, {"input": ["c"]}]}`), 0600)
+	assert.NoError(t, err)
+
+	tests, err := DiscoverTests(filename, nil, nil)
+	assert.NoError(t, err)
+
+	filter, err := NewFilter("", []string{"wip"}, nil)
+	assert.NoError(t, err)
+	tests, err = filter.Apply(tests)
+	assert.NoError(t, err)
+	assert.Len(t, tests, 1)
+
+	err = tests[0].UpdateExpected([]logstash.Event{{"message": "b"}}, []int{0})
+	assert.NoError(t, err)
+
+	got, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"testcases": [{"input": ["a"]}, {"input": ["b"], "tags": ["wip"],"expected": [{"message":"b"}]}, {"input": ["c"]}]}`, string(got))
+}
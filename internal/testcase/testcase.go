@@ -19,6 +19,7 @@ import (
 	"github.com/imkira/go-observer"
 	"github.com/mikefarah/yaml/v2"
 
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/diff"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logging"
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logstash"
 	lfvobserver "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/observer"
@@ -26,6 +27,18 @@ import (
 
 const DummyEventInputIndicator = "__lfv_dummy_event"
 
+// Match modes, which control how the actual events are compared with
+// the expected events.
+const (
+	// MatchExact requires the actual event to be equal to the expected
+	// event (except for the ignored fields).
+	MatchExact = "exact"
+
+	// MatchSubset only compares the fields of the actual event, which
+	// are present in the expected event. All other fields are ignored.
+	MatchSubset = "subset"
+)
+
 // TestCaseSet contains the configuration of a Logstash filter test case.
 // Most of the fields are supplied by the user via a JSON file or YAML file.
 type TestCaseSet struct {
@@ -33,6 +46,11 @@ type TestCaseSet struct {
 	// test case was read.
 	File string `json:"-" yaml:"-"`
 
+	// name is the slash separated path of the test case file relative to
+	// the test case directory, it is empty, if the test case file has not
+	// been discovered in a test case directory.
+	name string
+
 	// The unique ID of the input plugin in the tested configuration, where the
 	// test input is coming from. This is necessary, if a setup with multiple
 	// inputs is tested, which either have different codecs or are part of
@@ -96,12 +114,36 @@ type TestCaseSet struct {
 	// Optionally other information regarding the test case may be supplied.
 	TestCases []TestCase `json:"testcases" yaml:"testcases"`
 
+	// Match controls how the actual events are compared with the expected
+	// events, either MatchExact (default) or MatchSubset. With MatchSubset
+	// only the fields present in the expected event are compared.
+	Match string `json:"match" yaml:"match"`
+
+	// Tags contains a list of tags, which are applied to all test cases
+	// of this file. The tags are used to select the test cases to be
+	// executed (see Filter).
+	Tags []string `json:"tags" yaml:"tags"`
+
+	// Ordered controls if the actual events are compared with the expected
+	// events in the order they are received (default: true). If set to
+	// false, each expected event is compared with the actual event, which
+	// resembles it best, regardless of the order of the events.
+	Ordered *bool `json:"ordered" yaml:"ordered"`
+
 	// Events contains the fields for each event. These fields are filled
 	// in the New function. The sources are: InputFields, TestCase.Event and
 	// TestCase.InputLines
 	Events []logstash.FieldSet `json:"-" yaml:"-"`
 
 	descriptions []string
+	matchModes   []string
+
+	// positions contains the position of each test case in the test
+	// case file, if not all test cases are selected by a Filter.
+	positions []int
+
+	// fileTestCases is the number of test cases in the test case file.
+	fileTestCases int
 }
 
 // TestCase is a pair of an input line that should be fed
@@ -124,6 +166,14 @@ type TestCase struct {
 	// Description contains an optional description of the test case
 	// which will be printed while the tests are executed.
 	Description string `json:"description" yaml:"description"`
+
+	// Match overwrites the match mode of the test case set for the
+	// expected events of this test case.
+	Match string `json:"match" yaml:"match"`
+
+	// Tags contains a list of tags of this test case in addition to the
+	// tags of the test case set.
+	Tags []string `json:"tags" yaml:"tags"`
 }
 
 var (
@@ -225,7 +275,27 @@ func New(reader io.Reader, configType string) (*TestCaseSet, error) {
 	tcs.IgnoredFields = append(tcs.IgnoredFields, defaultIgnoredFields...)
 	sort.Strings(tcs.IgnoredFields)
 
+	if err = validateMatchMode(tcs.Match); err != nil {
+		return nil, err
+	}
+
+	tcs.fileTestCases = len(tcs.TestCases)
+	if err = tcs.prepareTestCases(); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Current TestCaseSet after converting fields: %+v", tcs)
+	return &tcs, nil
+}
+
+// prepareTestCases fills the input lines, events and expected events of
+// the test case set from its test cases.
+func (tcs *TestCaseSet) prepareTestCases() error {
+	tcs.InputLines = nil
+	tcs.ExpectedEvents = nil
+	tcs.Events = nil
 	tcs.descriptions = make([]string, 0, 100)
+	tcs.matchModes = make([]string, 0, 100)
 
 	for _, tc := range tcs.TestCases {
 		// Add event, if there are no input lines.
@@ -243,13 +313,53 @@ func New(reader io.Reader, configType string) (*TestCaseSet, error) {
 				tcs.Events[len(tcs.Events)-1][k] = v
 			}
 		}
+		if err := validateMatchMode(tc.Match); err != nil {
+			return err
+		}
+		matchMode := tc.Match
+		if matchMode == "" {
+			matchMode = tcs.Match
+		}
 		for range tc.ExpectedEvents {
 			tcs.descriptions = append(tcs.descriptions, tc.Description)
+			tcs.matchModes = append(tcs.matchModes, matchMode)
 		}
 	}
 
-	log.Debugf("Current TestCaseSet after converting fields: %+v", tcs)
-	return &tcs, nil
+	return nil
+}
+
+// validateMatchMode returns an error, if the match mode is not supported.
+// An empty match mode is valid and results in the default match mode.
+func validateMatchMode(matchMode string) error {
+	switch matchMode {
+	case "", MatchExact, MatchSubset:
+		return nil
+	default:
+		return fmt.Errorf("unsupported match mode %q, supported modes: %s, %s", matchMode, MatchExact, MatchSubset)
+	}
+}
+
+// matchMode returns the match mode for the i-th expected event.
+func (tcs *TestCaseSet) matchMode(i int) string {
+	if len(tcs.matchModes) > i && tcs.matchModes[i] != "" {
+		return tcs.matchModes[i]
+	}
+	if tcs.Match != "" {
+		return tcs.Match
+	}
+	return MatchExact
+}
+
+// Name returns the path of the test case file relative to the test case
+// directory or, if the test case file has not been discovered in a test
+// case directory, its base name. The name identifies the test case file in
+// the test results.
+func (tcs *TestCaseSet) Name() string {
+	if tcs.name != "" {
+		return tcs.name
+	}
+	return filepath.Base(tcs.File)
 }
 
 // NewFromFile reads a test case configuration from an on-disk file.
@@ -278,17 +388,44 @@ func NewFromFile(path string) (*TestCaseSet, error) {
 }
 
 // Compare compares a slice of events against the expected events of
-// this test case. Each event is written pretty-printed to a temporary
-// file and the two files are passed to the diff command. Its output is
-// is sent to the observer via an lfvobserver.ComparisonResult struct.
+// this test case. If no diff command is given, the events are compared
+// field by field with the builtin comparator. Otherwise each event is
+// written pretty-printed to a temporary file and the two files are passed
+// to the diff command. The result of the comparison is sent to the
+// observer via an lfvobserver.ComparisonResult struct.
+// If the test case set is not ordered, the expected events are paired with
+// the actual events, which resemble them best, and the events, which could
+// not be paired, are reported as failures.
 // Returns true if the current test case passes, otherwise false. A non-nil
 // error value indicates a problem executing the test.
 func (tcs *TestCaseSet) Compare(events []logstash.Event, diffCommand []string, liveProducer observer.Property) (bool, error) {
+	return tcs.CompareWithDetails(events, Details{}, diffCommand, liveProducer)
+}
+
+// Details contains additional information about the processing of the
+// actual events, which is added to the comparison results.
+type Details struct {
+	// Traces contains the filter trace for each of the actual events, it
+	// may be shorter than the events, if no trace is available.
+	Traces []string
+
+	// Dropped describes the input events, which have been dropped by a
+	// drop filter.
+	Dropped []string
+}
+
+// CompareWithDetails compares the events like Compare and adds the filter
+// trace of the actual event as well as the dropped events to the
+// comparison results.
+func (tcs *TestCaseSet) CompareWithDetails(events []logstash.Event, details Details, diffCommand []string, liveProducer observer.Property) (bool, error) {
 	status := true
+	traces := details.Traces
+
+	ordered := tcs.Ordered == nil || *tcs.Ordered
 
 	// Don't even attempt to do a deep comparison of the event
 	// lists unless their lengths are equal.
-	if len(tcs.ExpectedEvents) != len(events) {
+	if ordered && len(tcs.ExpectedEvents) != len(events) {
 		eventsJSON, err := json.MarshalIndent(events, "", "  ")
 		if err != nil {
 			return false, err
@@ -296,9 +433,12 @@ func (tcs *TestCaseSet) Compare(events []logstash.Event, diffCommand []string, l
 		comparisonResult := lfvobserver.ComparisonResult{
 			Status:     false,
 			Name:       "Compare actual event with expected event",
-			Explain:    fmt.Sprintf("Expected %d event(s), got %d instead.\nReceived events: %s", len(tcs.ExpectedEvents), len(events), string(eventsJSON)),
-			Path:       filepath.Base(tcs.File),
+			Explain:    fmt.Sprintf("Expected %d event(s), got %d instead.\nReceived events: %s", len(tcs.ExpectedEvents), len(events), string(eventsJSON)) + explainDropped(details.Dropped),
+			Path:       tcs.Name(),
 			EventIndex: 0,
+			Expected:   tcs.ExpectedEvents,
+			Actual:     events,
+			Trace:      allTraces(traces),
 		}
 		liveProducer.Update(comparisonResult)
 		return false, nil
@@ -306,63 +446,64 @@ func (tcs *TestCaseSet) Compare(events []logstash.Event, diffCommand []string, l
 
 	// Make sure we produce a result even if there are zero events (i.e. we
 	// won't enter the for loop below).
-	if len(events) == 0 {
+	if len(events) == 0 && len(tcs.ExpectedEvents) == 0 {
 		comparisonResult := lfvobserver.ComparisonResult{
 			Status:     true,
 			Name:       "Compare actual event with expected event",
 			Explain:    "Drop all events",
-			Path:       filepath.Base(tcs.File),
+			Path:       tcs.Name(),
 			EventIndex: 0,
+			Expected:   tcs.ExpectedEvents,
+			Actual:     events,
 		}
 		liveProducer.Update(comparisonResult)
 		return true, nil
 	}
 
-	tempdir, err := os.MkdirTemp("", "")
-	if err != nil {
-		return false, err
-	}
-	defer func() {
-		if err := os.RemoveAll(tempdir); err != nil {
-			log.Errorf("Problem deleting temporary directory: %s", err)
-		}
-	}()
-
-	for i, actualEvent := range events {
-		comparisonResult := lfvobserver.ComparisonResult{
-			Path:       filepath.Base(tcs.File),
-			EventIndex: i,
-			Status:     true,
-		}
-		if (len(tcs.descriptions) > i) && (len(tcs.descriptions[i]) > 0) {
-			comparisonResult.Name = fmt.Sprintf("Comparing message %d of %d (%s)", i+1, len(events), tcs.descriptions[i])
-		} else {
-			comparisonResult.Name = fmt.Sprintf("Comparing message %d of %d", i+1, len(events))
+	var tempdir string
+	if len(diffCommand) > 0 {
+		var err error
+		tempdir, err = os.MkdirTemp("", "")
+		if err != nil {
+			return false, err
 		}
+		defer func() {
+			if err := os.RemoveAll(tempdir); err != nil {
+				log.Errorf("Problem deleting temporary directory: %s", err)
+			}
+		}()
+	}
 
-		// Ignored fields can be in a sub object
+	// Ignored fields can be in a sub object
+	for _, actualEvent := range events {
 		for _, ignored := range tcs.IgnoredFields {
 			removeFields(ignored, actualEvent)
 		}
+	}
 
-		// Create a directory structure for the JSON file being
-		// compared that makes it easy for the user to identify
-		// the failing test case in the diff output:
-		// $TMP/<random>/<test case file>/<event #>/<actual|expected>
-		resultDir := filepath.Join(tempdir, filepath.Base(tcs.File), strconv.Itoa(i+1))
-		actualFilePath := filepath.Join(resultDir, "actual")
-		if err = marshalToFile(actualEvent, actualFilePath); err != nil {
-			return false, err
+	var (
+		pairs             []eventPair
+		unmatchedExpected []int
+		unmatchedActual   []int
+	)
+	if ordered {
+		for i := range events {
+			pairs = append(pairs, eventPair{expected: i, actual: i})
 		}
-		expectedFilePath := filepath.Join(resultDir, "expected")
-		if err = marshalToFile(tcs.ExpectedEvents[i], expectedFilePath); err != nil {
+	} else {
+		var err error
+		pairs, unmatchedExpected, unmatchedActual, err = tcs.matchEvents(events)
+		if err != nil {
 			return false, err
 		}
+	}
 
-		comparisonResult.Status, comparisonResult.Explain, err = runDiffCommand(diffCommand, expectedFilePath, actualFilePath)
+	for _, pair := range pairs {
+		comparisonResult, err := tcs.compareEvent(pair.expected, events[pair.actual], diffCommand, tempdir)
 		if err != nil {
 			return false, err
 		}
+		comparisonResult.Trace = traceOf(traces, pair.actual)
 		if !comparisonResult.Status {
 			status = false
 		}
@@ -370,9 +511,212 @@ func (tcs *TestCaseSet) Compare(events []logstash.Event, diffCommand []string, l
 		liveProducer.Update(comparisonResult)
 	}
 
+	for _, i := range unmatchedExpected {
+		status = false
+		eventJSON, err := json.MarshalIndent(tcs.ExpectedEvents[i], "", "  ")
+		if err != nil {
+			return false, err
+		}
+		comparisonResult := lfvobserver.ComparisonResult{
+			Status:     false,
+			Name:       fmt.Sprintf("Missing message %d of %d", i+1, len(tcs.ExpectedEvents)),
+			Explain:    fmt.Sprintf("No actual event matches the expected event: %s", string(eventJSON)) + explainDropped(details.Dropped),
+			Path:       tcs.Name(),
+			EventIndex: i,
+			Expected:   tcs.ExpectedEvents[i],
+		}
+		if (len(tcs.descriptions) > i) && (len(tcs.descriptions[i]) > 0) {
+			comparisonResult.Description = tcs.descriptions[i]
+			comparisonResult.Name = fmt.Sprintf("Missing message %d of %d (%s)", i+1, len(tcs.ExpectedEvents), tcs.descriptions[i])
+		}
+		liveProducer.Update(comparisonResult)
+	}
+
+	for _, j := range unmatchedActual {
+		status = false
+		eventJSON, err := json.MarshalIndent(events[j], "", "  ")
+		if err != nil {
+			return false, err
+		}
+		comparisonResult := lfvobserver.ComparisonResult{
+			Status:     false,
+			Name:       fmt.Sprintf("Unexpected message %d of %d", j+1, len(events)),
+			Explain:    fmt.Sprintf("No expected event matches the actual event: %s", string(eventJSON)),
+			Path:       tcs.Name(),
+			EventIndex: j,
+			Actual:     events[j],
+			Trace:      traceOf(traces, j),
+		}
+		liveProducer.Update(comparisonResult)
+	}
+
 	return status, nil
 }
 
+// traceOf returns the filter trace of the i-th actual event or an empty
+// string, if there is none.
+func traceOf(traces []string, i int) string {
+	if i < len(traces) {
+		return traces[i]
+	}
+	return ""
+}
+
+// allTraces returns the filter traces of all the actual events, each
+// introduced by the number of the event.
+func allTraces(traces []string) string {
+	var sb strings.Builder
+	for i, trace := range traces {
+		if trace == "" {
+			continue
+		}
+		fmt.Fprintf(&sb, "Event %d:\n%s", i+1, trace)
+	}
+	return sb.String()
+}
+
+// explainDropped returns the explanation of the dropped events, which is
+// appended to the explanation of missing events.
+func explainDropped(dropped []string) string {
+	if len(dropped) == 0 {
+		return ""
+	}
+	return "\nDropped events:\n  " + strings.Join(dropped, "\n  ")
+}
+
+// eventPair references an expected event and the actual event it is
+// compared with by their index.
+type eventPair struct {
+	expected int
+	actual   int
+}
+
+// matchEvents finds the pairs of expected and actual events, which
+// resemble each other best, regardless of their order. The distance
+// between two events is the number of differences found by the builtin
+// comparator. The indices of the expected and actual events, which could
+// not be paired, because the number of events differs, are returned as
+// well.
+func (tcs *TestCaseSet) matchEvents(events []logstash.Event) ([]eventPair, []int, []int, error) {
+	size := len(tcs.ExpectedEvents)
+	if len(events) > size {
+		size = len(events)
+	}
+
+	// Rows and columns beyond the number of expected and actual events
+	// respectively are padding with zero cost to get a square matrix.
+	cost := make([][]int, size)
+	for i := range cost {
+		cost[i] = make([]int, size)
+		if i >= len(tcs.ExpectedEvents) {
+			continue
+		}
+		for j := range events {
+			actualEvent, err := tcs.reduceEvent(i, events[j])
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			differences, err := diff.Compare(tcs.ExpectedEvents[i], actualEvent)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			cost[i][j] = len(differences)
+		}
+	}
+
+	var (
+		pairs             []eventPair
+		unmatchedExpected []int
+		unmatchedActual   []int
+	)
+	for i, j := range minCostAssignment(cost) {
+		switch {
+		case i >= len(tcs.ExpectedEvents):
+			unmatchedActual = append(unmatchedActual, j)
+		case j >= len(events):
+			unmatchedExpected = append(unmatchedExpected, i)
+		default:
+			pairs = append(pairs, eventPair{expected: i, actual: j})
+		}
+	}
+	sort.Ints(unmatchedActual)
+
+	return pairs, unmatchedExpected, unmatchedActual, nil
+}
+
+// reduceEvent reduces the actual event according to the match mode of
+// the i-th expected event.
+func (tcs *TestCaseSet) reduceEvent(i int, actualEvent logstash.Event) (logstash.Event, error) {
+	if tcs.matchMode(i) != MatchSubset {
+		return actualEvent, nil
+	}
+	reduced, err := diff.Subset(tcs.ExpectedEvents[i], actualEvent)
+	if err != nil {
+		return nil, err
+	}
+	actualEvent, _ = reduced.(map[string]interface{})
+	return actualEvent, nil
+}
+
+// compareEvent compares the actual event with the i-th expected event,
+// either with the builtin comparator or with the diff command.
+func (tcs *TestCaseSet) compareEvent(i int, actualEvent logstash.Event, diffCommand []string, tempdir string) (lfvobserver.ComparisonResult, error) {
+	comparisonResult := lfvobserver.ComparisonResult{
+		Path:       tcs.Name(),
+		EventIndex: i,
+		Status:     true,
+	}
+	if (len(tcs.descriptions) > i) && (len(tcs.descriptions[i]) > 0) {
+		comparisonResult.Description = tcs.descriptions[i]
+		comparisonResult.Name = fmt.Sprintf("Comparing message %d of %d (%s)", i+1, len(tcs.ExpectedEvents), tcs.descriptions[i])
+	} else {
+		comparisonResult.Name = fmt.Sprintf("Comparing message %d of %d", i+1, len(tcs.ExpectedEvents))
+	}
+
+	actualEvent, err := tcs.reduceEvent(i, actualEvent)
+	if err != nil {
+		return comparisonResult, err
+	}
+
+	comparisonResult.Expected = tcs.ExpectedEvents[i]
+	comparisonResult.Actual = actualEvent
+
+	if len(diffCommand) == 0 {
+		differences, err := diff.Compare(tcs.ExpectedEvents[i], actualEvent)
+		if err != nil {
+			return comparisonResult, err
+		}
+		comparisonResult.Status = len(differences) == 0
+		comparisonResult.Explain = diff.Explain(differences)
+		comparisonResult.Differences = differences
+		return comparisonResult, nil
+	}
+
+	// Create a directory structure for the JSON file being
+	// compared that makes it easy for the user to identify
+	// the failing test case in the diff output:
+	// $TMP/<random>/<test case file>/<event #>/<actual|expected>
+	resultDir := filepath.Join(tempdir, filepath.FromSlash(tcs.Name()), strconv.Itoa(i+1))
+	actualFilePath := filepath.Join(resultDir, "actual")
+	if err := marshalToFile(actualEvent, actualFilePath); err != nil {
+		return comparisonResult, err
+	}
+	// Matchers are not understood by the diff command, therefore
+	// the satisfied matchers are replaced with the actual values.
+	resolved, err := diff.Resolve(tcs.ExpectedEvents[i], actualEvent)
+	if err != nil {
+		return comparisonResult, err
+	}
+	expectedEvent, _ := resolved.(map[string]interface{})
+	expectedFilePath := filepath.Join(resultDir, "expected")
+	if err := marshalToFile(expectedEvent, expectedFilePath); err != nil {
+		return comparisonResult, err
+	}
+
+	comparisonResult.Status, comparisonResult.Explain, err = runDiffCommand(diffCommand, expectedFilePath, actualFilePath)
+	return comparisonResult, err
+}
+
 // marshalToFile pretty-prints a logstash.Event and writes it to a
 // file, creating the file's parent directories as necessary.
 func marshalToFile(event logstash.Event, filename string) error {
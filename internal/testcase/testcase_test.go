@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logstash"
+	lfvobserver "github.com/magnusbaeck/logstash-filter-verifier/v2/internal/observer"
 )
 
 func TestNew_Success(t *testing.T) {
@@ -132,6 +133,16 @@ func TestNew_Failure(t *testing.T) {
 			input:         `{"expected": [{"test": "test"}]}`,
 			expectedError: `testcase file contained deprecated "expected" key`,
 		},
+		// Return error if an unsupported match mode is used.
+		{
+			input:         `{"match": "fuzzy"}`,
+			expectedError: `unsupported match mode "fuzzy"`,
+		},
+		// Return error if an unsupported match mode is used in a test case.
+		{
+			input:         `{"testcases": [{"match": "fuzzy"}]}`,
+			expectedError: `unsupported match mode "fuzzy"`,
+		},
 	}
 	for i, c := range cases {
 		_, err := New(bytes.NewReader([]byte(c.input)), "json")
@@ -404,6 +415,223 @@ func TestCompare(t *testing.T) {
 			true,
 			nil,
 		},
+		// Same field with different values, builtin comparator.
+		{
+			&TestCaseSet{
+				File: "/path/to/filename.json",
+				InputFields: logstash.FieldSet{
+					"type": "test",
+				},
+				Codec: "line",
+				ExpectedEvents: []logstash.Event{
+					{
+						"a": "b",
+					},
+				},
+			},
+			[]logstash.Event{
+				{
+					"a": "B",
+				},
+			},
+			nil,
+			false,
+			nil,
+		},
+		// Equal events with nested fields, builtin comparator.
+		{
+			&TestCaseSet{
+				File: "/path/to/filename.json",
+				InputFields: logstash.FieldSet{
+					"type": "test",
+				},
+				Codec: "line",
+				ExpectedEvents: []logstash.Event{
+					{
+						"a": map[string]interface{}{
+							"b": []interface{}{"c", 1},
+						},
+					},
+				},
+			},
+			[]logstash.Event{
+				{
+					"a": map[string]interface{}{
+						"b": []interface{}{"c", 1.0},
+					},
+				},
+			},
+			nil,
+			true,
+			nil,
+		},
+		// Additional fields are ignored in subset match mode.
+		{
+			&TestCaseSet{
+				File: "/path/to/filename.json",
+				InputFields: logstash.FieldSet{
+					"type": "test",
+				},
+				Codec: "line",
+				Match: MatchSubset,
+				ExpectedEvents: []logstash.Event{
+					{
+						"a": map[string]interface{}{
+							"b": "c",
+						},
+					},
+				},
+			},
+			[]logstash.Event{
+				{
+					"a": map[string]interface{}{
+						"b": "c",
+						"d": "e",
+					},
+					"f": "g",
+				},
+			},
+			nil,
+			true,
+			nil,
+		},
+		// Missing fields are reported in subset match mode.
+		{
+			&TestCaseSet{
+				File: "/path/to/filename.json",
+				InputFields: logstash.FieldSet{
+					"type": "test",
+				},
+				Codec: "line",
+				Match: MatchSubset,
+				ExpectedEvents: []logstash.Event{
+					{
+						"a": "b",
+					},
+				},
+			},
+			[]logstash.Event{
+				{
+					"f": "g",
+				},
+			},
+			[]string{"diff"},
+			false,
+			nil,
+		},
+		// Match mode of the test case overwrites the match mode of the set.
+		{
+			&TestCaseSet{
+				File: "/path/to/filename.json",
+				InputFields: logstash.FieldSet{
+					"type": "test",
+				},
+				Codec: "line",
+				Match: MatchSubset,
+				ExpectedEvents: []logstash.Event{
+					{
+						"a": "b",
+					},
+				},
+				matchModes: []string{MatchExact},
+			},
+			[]logstash.Event{
+				{
+					"a": "b",
+					"f": "g",
+				},
+			},
+			nil,
+			false,
+			nil,
+		},
+		// Events in different order are paired, if not ordered.
+		{
+			&TestCaseSet{
+				File: "/path/to/filename.json",
+				InputFields: logstash.FieldSet{
+					"type": "test",
+				},
+				Codec:   "line",
+				Ordered: boolPtr(false),
+				ExpectedEvents: []logstash.Event{
+					{
+						"a": "b",
+					},
+					{
+						"c": "d",
+					},
+				},
+			},
+			[]logstash.Event{
+				{
+					"c": "d",
+				},
+				{
+					"a": "b",
+				},
+			},
+			[]string{"diff"},
+			true,
+			nil,
+		},
+		// Events in different order fail, if ordered.
+		{
+			&TestCaseSet{
+				File: "/path/to/filename.json",
+				InputFields: logstash.FieldSet{
+					"type": "test",
+				},
+				Codec:   "line",
+				Ordered: boolPtr(true),
+				ExpectedEvents: []logstash.Event{
+					{
+						"a": "b",
+					},
+					{
+						"c": "d",
+					},
+				},
+			},
+			[]logstash.Event{
+				{
+					"c": "d",
+				},
+				{
+					"a": "b",
+				},
+			},
+			nil,
+			false,
+			nil,
+		},
+		// Unmatched events fail, if not ordered.
+		{
+			&TestCaseSet{
+				File: "/path/to/filename.json",
+				InputFields: logstash.FieldSet{
+					"type": "test",
+				},
+				Codec:   "line",
+				Ordered: boolPtr(false),
+				ExpectedEvents: []logstash.Event{
+					{
+						"a": "b",
+					},
+				},
+			},
+			[]logstash.Event{
+				{
+					"c": "d",
+				},
+				{
+					"a": "b",
+				},
+			},
+			nil,
+			false,
+			nil,
+		},
 		// Diff command execution errors are propagated correctly.
 		{
 			&TestCaseSet{
@@ -441,6 +669,85 @@ func TestCompare(t *testing.T) {
 	}
 }
 
+func TestCompareWithDetails(t *testing.T) {
+	dropped := []string{"event 1 dropped by plugin `drop_healthchecks` in `filters/10-web.conf` (if [path] == \"/health\")"}
+
+	cases := []struct {
+		name         string
+		ordered      bool
+		actualEvents []logstash.Event
+	}{
+		{
+			name:    "ordered with missing event",
+			ordered: true,
+		},
+		{
+			name:         "unordered with missing event",
+			ordered:      false,
+			actualEvents: []logstash.Event{{"a": "c"}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			liveObserver := observer.NewProperty(nil)
+			ordered := c.ordered
+			tcs := &TestCaseSet{
+				File:           "/path/to/filename.json",
+				Ordered:        &ordered,
+				ExpectedEvents: []logstash.Event{{"a": "b"}, {"a": "c"}},
+			}
+
+			result, err := tcs.CompareWithDetails(c.actualEvents, Details{Dropped: dropped}, nil, liveObserver)
+			assert.NoError(t, err)
+			assert.False(t, result)
+
+			comparisonResult, ok := liveObserver.Value().(lfvobserver.ComparisonResult)
+			assert.True(t, ok)
+			assert.Contains(t, comparisonResult.Explain, "Dropped events:\n  "+dropped[0])
+		})
+	}
+}
+
+func TestMatchEvents(t *testing.T) {
+	tcs := &TestCaseSet{
+		ExpectedEvents: []logstash.Event{
+			{"message": "a", "tags": []interface{}{"x"}},
+			{"message": "b"},
+			{"message": "c"},
+		},
+	}
+	events := []logstash.Event{
+		{"message": "c"},
+		{"message": "unknown"},
+		{"message": "a", "tags": []interface{}{"y"}},
+		{"message": "b"},
+	}
+
+	pairs, unmatchedExpected, unmatchedActual, err := tcs.matchEvents(events)
+	if err != nil {
+		t.Fatalf("Expected no error, got error: %s", err)
+	}
+
+	assert.ElementsMatch(t, []eventPair{{0, 2}, {1, 3}, {2, 0}}, pairs)
+	assert.Empty(t, unmatchedExpected)
+	assert.Equal(t, []int{1}, unmatchedActual)
+}
+
+func TestMinCostAssignment(t *testing.T) {
+	cost := [][]int{
+		{4, 1, 3},
+		{2, 0, 5},
+		{3, 2, 2},
+	}
+
+	assert.Equal(t, []int{1, 0, 2}, minCostAssignment(cost))
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
 func TestMarshalToFile(t *testing.T) {
 	// Implicitly test that subdirectories are created as needed.
 	fullpath := filepath.Join(t.TempDir(), "a", "b", "c.json")
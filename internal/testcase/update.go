@@ -0,0 +1,565 @@
+package testcase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/diff"
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logstash"
+)
+
+// UpdateExpected rewrites the expected events of the test cases in the
+// test case file with the given actual events. The format of the file
+// (JSON or YAML) as well as all the other properties of the test cases
+// (e.g. the descriptions) are preserved. Matchers in the expected events,
+// which are satisfied by the actual events, are preserved as well.
+//
+// Test cases with match mode subset are written with the fields of the
+// current expected events only.
+//
+// inputIDs optionally contains for each event the index of the input line,
+// the event originates from, which is used to assign the events to the
+// test cases. Without inputIDs, the events are assigned to the test cases
+// in the order of the current expected events, which requires the number of
+// events to stay the same, unless there is only a single test case.
+func (tcs *TestCaseSet) UpdateExpected(events []logstash.Event, inputIDs []int) error {
+	if len(tcs.TestCases) == 0 {
+		return fmt.Errorf("test case file %s does not contain any test cases to update", tcs.File)
+	}
+
+	for _, event := range events {
+		for _, ignored := range tcs.IgnoredFields {
+			removeFields(ignored, event)
+		}
+	}
+
+	groups, err := tcs.groupEvents(events, inputIDs)
+	if err != nil {
+		return err
+	}
+
+	offset := 0
+	for t, tc := range tcs.TestCases {
+		expected := tcs.ExpectedEvents[offset : offset+len(tc.ExpectedEvents)]
+		offset += len(tc.ExpectedEvents)
+
+		// Reduce the events of test cases with match mode subset to the
+		// fields of the expected events, such that the test cases keep
+		// comparing only these fields. Additional events are reduced to
+		// the fields of the last expected event.
+		if tcs.testCaseMatchMode(tc) == MatchSubset && len(expected) > 0 {
+			for k := range groups[t] {
+				groups[t][k], err = diff.Subset(expected[min(k, len(expected)-1)], groups[t][k])
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		// Preserve the matchers, if the number of events of a test case
+		// did not change.
+		if len(groups[t]) == len(expected) {
+			for k := range groups[t] {
+				groups[t][k], err = diff.Preserve(expected[k], groups[t][k])
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Map the groups to the positions of the test cases in the file. The
+	// test cases, which are not selected by a filter, are left untouched.
+	fileGroups := make([][]interface{}, tcs.fileTestCases)
+	for t := range groups {
+		fileGroups[tcs.position(t)] = groups[t]
+	}
+
+	fi, err := os.Stat(tcs.File)
+	if err != nil {
+		return err
+	}
+	buf, err := os.ReadFile(tcs.File)
+	if err != nil {
+		return err
+	}
+
+	switch strings.TrimPrefix(filepath.Ext(tcs.File), ".") {
+	case "json":
+		buf, err = replaceExpectedJSON(buf, fileGroups)
+	case "yaml", "yml":
+		buf, err = replaceExpectedYAML(buf, fileGroups)
+	default:
+		err = fmt.Errorf("unsupported test case file type %q", filepath.Ext(tcs.File))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %s", tcs.File, err)
+	}
+
+	return os.WriteFile(tcs.File, buf, fi.Mode().Perm())
+}
+
+// testCaseMatchMode returns the match mode of the test case.
+func (tcs *TestCaseSet) testCaseMatchMode(tc TestCase) string {
+	if tc.Match != "" {
+		return tc.Match
+	}
+	return tcs.Match
+}
+
+// groupEvents assigns the events to the test cases.
+func (tcs *TestCaseSet) groupEvents(events []logstash.Event, inputIDs []int) ([][]interface{}, error) {
+	groups := make([][]interface{}, len(tcs.TestCases))
+
+	switch {
+	case inputIDs != nil:
+		if len(inputIDs) != len(events) {
+			return nil, fmt.Errorf("got %d input IDs for %d events", len(inputIDs), len(events))
+		}
+
+		// Index of the first input line of each test case. Test cases
+		// without input lines get a dummy event as input.
+		starts := make([]int, len(tcs.TestCases))
+		next := 0
+		for t, tc := range tcs.TestCases {
+			starts[t] = next
+			lines := len(tc.InputLines)
+			if lines == 0 {
+				lines = 1
+			}
+			next += lines
+		}
+
+		for k, event := range events {
+			if inputIDs[k] < 0 || inputIDs[k] >= next {
+				return nil, fmt.Errorf("event %d originates from unknown input line %d", k, inputIDs[k])
+			}
+			t := sort.Search(len(starts), func(i int) bool { return starts[i] > inputIDs[k] }) - 1
+			groups[t] = append(groups[t], map[string]interface{}(event))
+		}
+	case len(events) == len(tcs.ExpectedEvents):
+		k := 0
+		for t, tc := range tcs.TestCases {
+			for range tc.ExpectedEvents {
+				groups[t] = append(groups[t], map[string]interface{}(events[k]))
+				k++
+			}
+		}
+	case len(tcs.TestCases) == 1:
+		for _, event := range events {
+			groups[0] = append(groups[0], map[string]interface{}(event))
+		}
+	default:
+		return nil, fmt.Errorf("unable to assign %d events to the %d test cases in %s, expected %d events", len(events), len(tcs.TestCases), tcs.File, len(tcs.ExpectedEvents))
+	}
+
+	for t := range groups {
+		if groups[t] == nil {
+			groups[t] = []interface{}{}
+		}
+	}
+	return groups, nil
+}
+
+// replaceExpectedYAML replaces the expected events of each test case in
+// the YAML document with the respective group of events. Test cases with a
+// nil group are left untouched. Like with JSON, only the values of the
+// "expected" keys are replaced (or inserted), the rest of the document is
+// kept byte by byte. Documents with test cases in flow style are encoded
+// again as a whole, since the end of a node can not be determined in them.
+func replaceExpectedYAML(buf []byte, groups [][]interface{}) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(buf, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a mapping at the top level")
+	}
+	top := doc.Content[0]
+
+	testcases := yamlMappingValue(top, "testcases")
+	if testcases == nil || testcases.Kind != yaml.SequenceNode || len(testcases.Content) != len(groups) {
+		return nil, fmt.Errorf("expected a sequence of %d test cases", len(groups))
+	}
+
+	flow := top.Style&yaml.FlowStyle != 0 || testcases.Style&yaml.FlowStyle != 0
+	for t, tc := range testcases.Content {
+		if tc.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("expected test case %d to be a mapping", t+1)
+		}
+		if groups[t] != nil && (tc.Style&yaml.FlowStyle != 0 || len(tc.Content) == 0) {
+			flow = true
+		}
+	}
+	if flow {
+		return encodeExpectedYAML(&doc, testcases, groups)
+	}
+
+	lines := lineOffsets(buf)
+	var splices []byteSplice
+	for t, tc := range testcases.Content {
+		if groups[t] == nil {
+			continue
+		}
+
+		// The keys of a test case are indented like its first key, e.g.
+		// after "- ".
+		indent := tc.Content[0].Column - 1
+
+		i := yamlKeyIndex(tc, "expected")
+		if i == -1 {
+			last := len(tc.Content) - 2
+			end := yamlNodeEnd(buf, lines, yamlNodeAfter(top, testcases, t, last), indent, lines[tc.Content[last+1].Line-1])
+			value, err := encodeYAMLValue(groups[t], indent+2)
+			if err != nil {
+				return nil, err
+			}
+			insert := strings.Repeat(" ", indent) + "expected:" + string(value)
+			if end > 0 && buf[end-1] != '\n' {
+				insert = "\n" + insert
+			}
+			splices = append(splices, byteSplice{start: end, end: end, value: []byte(insert)})
+			continue
+		}
+
+		key, current := tc.Content[i], tc.Content[i+1]
+		start := bytes.IndexByte(buf[lines[key.Line-1]:], ':')
+		if start == -1 {
+			return nil, fmt.Errorf("expected a colon after the key in line %d", key.Line)
+		}
+		start += lines[key.Line-1] + 1
+		end := yamlNodeEnd(buf, lines, yamlNodeAfter(top, testcases, t, i), indent, start)
+
+		valueIndent := indent + 2
+		if current.Line > key.Line {
+			valueIndent = current.Column - 1
+		}
+		value, err := encodeYAMLValue(groups[t], valueIndent)
+		if err != nil {
+			return nil, err
+		}
+		splices = append(splices, byteSplice{start: start, end: end, value: value})
+	}
+
+	return applySplices(buf, splices), nil
+}
+
+// encodeExpectedYAML replaces the expected events of each test case in the
+// YAML document node with the respective group of events and encodes the
+// whole document. Comments and the order of the keys are preserved.
+func encodeExpectedYAML(doc *yaml.Node, testcases *yaml.Node, groups [][]interface{}) ([]byte, error) {
+	for t, tc := range testcases.Content {
+		if groups[t] == nil {
+			continue
+		}
+
+		var expected yaml.Node
+		if err := expected.Encode(groups[t]); err != nil {
+			return nil, err
+		}
+
+		if value := yamlMappingValue(tc, "expected"); value != nil {
+			*value = expected
+			continue
+		}
+		tc.Content = append(tc.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "expected"},
+			&expected,
+		)
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// encodeYAMLValue encodes the events as the value of a key, which starts
+// right after the colon of the key. The lines of a block value are
+// indented by indent spaces.
+func encodeYAMLValue(events []interface{}, indent int) ([]byte, error) {
+	if len(events) == 0 {
+		return []byte(" []\n"), nil
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(events); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	value := []byte("\n")
+	prefix := strings.Repeat(" ", indent)
+	for _, line := range strings.SplitAfter(out.String(), "\n") {
+		if strings.TrimSpace(line) != "" {
+			line = prefix + line
+		}
+		value = append(value, line...)
+	}
+	return value, nil
+}
+
+// yamlNodeAfter returns the node following the value of the key with index
+// i of the test case t in document order, i.e. the next key of the test
+// case, the next test case or the key following the test cases. If the
+// test case is at the end of the document, nil is returned.
+func yamlNodeAfter(top *yaml.Node, testcases *yaml.Node, t int, i int) *yaml.Node {
+	tc := testcases.Content[t]
+	if i+2 < len(tc.Content) {
+		return tc.Content[i+2]
+	}
+	if t+1 < len(testcases.Content) {
+		return testcases.Content[t+1]
+	}
+	for j := 1; j+1 < len(top.Content); j += 2 {
+		if top.Content[j] == testcases {
+			return top.Content[j+1]
+		}
+	}
+	return nil
+}
+
+// yamlNodeEnd returns the offset in buf, where a value ends, which is
+// followed by the node next (nil for the end of the document). Blank lines
+// and comment lines, which are not indented more than indent, are
+// considered to belong to the next node. The returned offset is not lower
+// than start.
+func yamlNodeEnd(buf []byte, lines []int, next *yaml.Node, indent int, start int) int {
+	end := len(buf)
+	if next != nil {
+		end = lines[next.Line-1]
+	}
+	for end > start {
+		lineStart := bytes.LastIndexByte(buf[:end-1], '\n') + 1
+		if lineStart < start {
+			break
+		}
+		line := buf[lineStart:end]
+		trimmed := bytes.TrimSpace(line)
+		lineIndent := len(line) - len(bytes.TrimLeft(line, " \t"))
+		if len(trimmed) > 0 && (trimmed[0] != '#' || lineIndent > indent) {
+			break
+		}
+		end = lineStart
+	}
+	return end
+}
+
+// lineOffsets returns the offsets of the beginnings of the lines in buf.
+func lineOffsets(buf []byte) []int {
+	lines := []int{0}
+	for i, b := range buf {
+		if b == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
+
+func yamlMappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	i := yamlKeyIndex(mapping, key)
+	if i == -1 {
+		return nil
+	}
+	return mapping.Content[i+1]
+}
+
+func yamlKeyIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// byteSplice describes the replacement of the bytes between start and end
+// with the given value.
+type byteSplice struct {
+	start int
+	end   int
+	value []byte
+}
+
+// applySplices applies the splices, which are ordered by their position
+// and do not overlap, to buf.
+func applySplices(buf []byte, splices []byteSplice) []byte {
+	for i := len(splices) - 1; i >= 0; i-- {
+		s := splices[i]
+		buf = append(buf[:s.start:s.start], append(s.value, buf[s.end:]...)...)
+	}
+	return buf
+}
+
+// replaceExpectedJSON replaces the expected events of each test case in
+// the JSON document with the respective group of events. Test cases with a
+// nil group are left untouched. Only the values of the "expected" keys are
+// replaced (or inserted), the rest of the document is kept byte by byte.
+func replaceExpectedJSON(buf []byte, groups [][]interface{}) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(buf))
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	var splices []byteSplice
+	indentUnit := ""
+	found := false
+	count := 0
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if indentUnit == "" {
+			indentUnit = lineIndent(buf, int(dec.InputOffset()))
+		}
+		if key != "testcases" {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		found = true
+		if err := expectDelim(dec, '['); err != nil {
+			return nil, err
+		}
+		for ; dec.More(); count++ {
+			if count >= len(groups) {
+				return nil, fmt.Errorf("expected %d test cases", len(groups))
+			}
+			if groups[count] == nil {
+				var raw json.RawMessage
+				if err := dec.Decode(&raw); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			splice, err := expectedSplice(dec, buf, groups[count], indentUnit)
+			if err != nil {
+				return nil, err
+			}
+			splices = append(splices, splice)
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return nil, err
+		}
+	}
+	if !found || count != len(groups) {
+		return nil, fmt.Errorf("expected an array of %d test cases", len(groups))
+	}
+
+	return applySplices(buf, splices), nil
+}
+
+// expectedSplice reads a test case object from the decoder and returns the
+// splice, which replaces the value of its "expected" key with events or
+// inserts the key, if it is not present.
+func expectedSplice(dec *json.Decoder, buf []byte, events []interface{}, indentUnit string) (byteSplice, error) {
+	if err := expectDelim(dec, '{'); err != nil {
+		return byteSplice{}, err
+	}
+	objectIndent := lineIndent(buf, int(dec.InputOffset())-1)
+
+	var (
+		splice    *byteSplice
+		keyIndent = objectIndent + indentUnit
+		lastEnd   = -1
+	)
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return byteSplice{}, err
+		}
+		keyIndent = lineIndent(buf, int(dec.InputOffset()))
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return byteSplice{}, err
+		}
+		lastEnd = int(dec.InputOffset())
+
+		if key == "expected" {
+			value, err := marshalIndentJSON(events, keyIndent, indentUnit)
+			if err != nil {
+				return byteSplice{}, err
+			}
+			splice = &byteSplice{start: lastEnd - len(raw), end: lastEnd, value: value}
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return byteSplice{}, err
+	}
+
+	if splice != nil {
+		return *splice, nil
+	}
+
+	value, err := marshalIndentJSON(events, keyIndent, indentUnit)
+	if err != nil {
+		return byteSplice{}, err
+	}
+	newline := "\n"
+	if indentUnit == "" {
+		newline = ""
+	}
+	if lastEnd == -1 {
+		// Empty object, insert after the opening brace.
+		closing := int(dec.InputOffset()) - 1
+		insert := newline + keyIndent + `"expected": ` + string(value) + newline + objectIndent + "}"
+		return byteSplice{start: closing, end: closing + 1, value: []byte(insert)}, nil
+	}
+	insert := "," + newline + keyIndent + `"expected": ` + string(value)
+	return byteSplice{start: lastEnd, end: lastEnd, value: []byte(insert)}, nil
+}
+
+func marshalIndentJSON(v interface{}, prefix, indent string) ([]byte, error) {
+	if indent == "" {
+		return json.Marshal(v)
+	}
+	return json.MarshalIndent(v, prefix, indent)
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %q, got %v", delim, tok)
+	}
+	return nil
+}
+
+// lineIndent returns the leading whitespace of the line containing the
+// byte at offset, if the line starts with whitespace only up to offset.
+func lineIndent(buf []byte, offset int) string {
+	if offset > len(buf) {
+		offset = len(buf)
+	}
+	start := bytes.LastIndexByte(buf[:offset], '\n') + 1
+	end := start
+	for end < offset && (buf[end] == ' ' || buf[end] == '\t') {
+		end++
+	}
+	return string(buf[start:end])
+}
@@ -0,0 +1,258 @@
+package testcase
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/magnusbaeck/logstash-filter-verifier/v2/internal/logstash"
+)
+
+func TestUpdateExpected(t *testing.T) {
+	cases := []struct {
+		name     string
+		filename string
+		content  string
+		events   []logstash.Event
+		inputIDs []int
+
+		wantContent string
+		wantErr     bool
+	}{
+		{
+			name:     "json replace and insert",
+			filename: "test.json",
+			content: `{
+  "ignore": ["@timestamp"],
+  "testcases": [
+    {
+      "input": ["a", "b"],
+      "expected": [{"message": "x"}],
+      "description": "first"
+    },
+    {
+      "input": ["c"]
+    }
+  ]
+}
+`,
+			events: []logstash.Event{
+				{"message": "a", "@timestamp": "now"},
+				{"message": "b"},
+				{"message": "c"},
+			},
+			inputIDs: []int{0, 1, 2},
+
+			wantContent: `{
+  "ignore": ["@timestamp"],
+  "testcases": [
+    {
+      "input": ["a", "b"],
+      "expected": [
+        {
+          "message": "a"
+        },
+        {
+          "message": "b"
+        }
+      ],
+      "description": "first"
+    },
+    {
+      "input": ["c"],
+      "expected": [
+        {
+          "message": "c"
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+		{
+			name:     "json preserve matchers without input IDs",
+			filename: "test.json",
+			content:  `{"testcases": [{"input": ["a"], "expected": [{"message": "x", "id": {"$regex": "^[0-9]+$"}}]}]}`,
+			events: []logstash.Event{
+				{"message": "a", "id": "123"},
+			},
+
+			wantContent: `{"testcases": [{"input": ["a"], "expected": [{"id":{"$regex":"^[0-9]+$"},"message":"a"}]}]}`,
+		},
+		{
+			name:     "yaml",
+			filename: "test.yml",
+			content: `# Test case file
+testcases:
+  - input:
+      - a
+    # The first test case
+    description: first
+    expected:
+      - message: x
+  - input:
+      - b
+`,
+			events: []logstash.Event{
+				{"message": "a"},
+				{"message": "b", "tags": []interface{}{"t"}},
+			},
+			inputIDs: []int{0, 1},
+
+			wantContent: `# Test case file
+testcases:
+  - input:
+      - a
+    # The first test case
+    description: first
+    expected:
+      - message: a
+  - input:
+      - b
+    expected:
+      - message: b
+        tags:
+          - t
+`,
+		},
+		{
+			name:     "yaml subset",
+			filename: "test.yml",
+			content: `match: subset
+testcases:
+  - input:
+      - a
+      - b
+    expected:
+      - message: x
+`,
+			events: []logstash.Event{
+				{"message": "a", "host": "h1"},
+				{"message": "b", "host": "h2"},
+			},
+			inputIDs: []int{0, 1},
+
+			wantContent: `match: subset
+testcases:
+  - input:
+      - a
+      - b
+    expected:
+      - message: a
+      - message: b
+`,
+		},
+		{
+			name:     "yaml keep formatting",
+			filename: "test.yml",
+			content: `testcases:
+- input: ["a", 'b']
+  expected:
+  - message: x
+    # stale
+  description: "first"
+
+# The second test case
+- input:   [c]
+  expected: []
+# trailing comment
+`,
+			events: []logstash.Event{
+				{"message": "a"},
+				{"message": "b"},
+				{"message": "c"},
+			},
+			inputIDs: []int{0, 1, 2},
+
+			wantContent: `testcases:
+- input: ["a", 'b']
+  expected:
+  - message: a
+  - message: b
+  description: "first"
+
+# The second test case
+- input:   [c]
+  expected:
+    - message: c
+# trailing comment
+`,
+		},
+		{
+			name:     "yaml insert and empty",
+			filename: "test.yml",
+			content: `testcases:
+    - input: [a]
+      description: first
+    - input: [b]
+      expected:
+          - message: x
+`,
+			events: []logstash.Event{
+				{"message": "a"},
+			},
+			inputIDs: []int{0},
+
+			wantContent: `testcases:
+    - input: [a]
+      description: first
+      expected:
+        - message: a
+    - input: [b]
+      expected: []
+`,
+		},
+		{
+			name:     "yaml flow style",
+			filename: "test.yml",
+			content:  `{testcases: [{input: [a]}]}`,
+			events: []logstash.Event{
+				{"message": "a"},
+			},
+			inputIDs: []int{0},
+
+			wantContent: `{testcases: [{input: [a], expected: [{message: a}]}]}
+`,
+		},
+		{
+			name:     "ambiguous assignment",
+			filename: "test.json",
+			content:  `{"testcases": [{"input": ["a"]}, {"input": ["b"]}]}`,
+			events: []logstash.Event{
+				{"message": "a"},
+				{"message": "b"},
+			},
+
+			wantErr: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), test.filename)
+			err := os.WriteFile(filename, []byte(test.content), 0600)
+			assert.NoError(t, err)
+
+			tcs, err := NewFromFile(filename)
+			assert.NoError(t, err)
+
+			err = tcs.UpdateExpected(test.events, test.inputIDs)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			got, err := os.ReadFile(filename)
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantContent, string(got))
+
+			// The updated file must still be a valid test case file.
+			_, err = NewFromFile(filename)
+			assert.NoError(t, err)
+		})
+	}
+}